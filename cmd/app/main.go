@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,10 +22,17 @@ import (
 	"socialmediafeed/internal/hashtag"
 	"socialmediafeed/internal/infrastructure/database"
 	"socialmediafeed/internal/infrastructure/repository"
+	"socialmediafeed/internal/mute"
 	"socialmediafeed/internal/notification"
 	"socialmediafeed/internal/post"
+	"socialmediafeed/internal/reconcile"
+	"socialmediafeed/internal/report"
+	"socialmediafeed/internal/search"
 	"socialmediafeed/internal/user"
+	"socialmediafeed/internal/web"
 	"socialmediafeed/pkg/logger"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
 )
 
 func main() {
@@ -42,6 +53,23 @@ func main() {
 		logger.Fatal("Failed to create database directory: %v", err)
 	}
 
+	cursorSecret := getEnv("CURSOR_SECRET", "")
+	if cursorSecret == "" {
+		secretPath := filepath.Join(dbDir, "cursor.secret")
+		cursorSecret, err = loadOrCreateSecret(secretPath)
+		if err != nil {
+			logger.Fatal("Failed to load cursor secret: %v", err)
+		}
+		logger.Warning("CURSOR_SECRET is not set, signing cursors with the key stored in %s; set CURSOR_SECRET to the same value on every instance", secretPath)
+	}
+
+	pagination.Configure(
+		cursorSecret,
+		getEnvInt("PAGE_SIZE", pagination.DefaultLimit),
+		getEnvInt("MAX_PAGE_SIZE", pagination.DefaultMaxLimit),
+	)
+	web.ConfigureCSRF(getEnv("CSRF_SECRET", ""))
+
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		logger.Fatal("Failed to open database: %v", err)
@@ -63,6 +91,10 @@ func main() {
 	commentRepo := repository.NewCommentRepository(db)
 	hashtagRepo := repository.NewHashtagRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	reconcileRepo := repository.NewReconcileRepository(db)
+	muteRepo := repository.NewMuteRepository(db)
+	reportRepo := repository.NewReportRepository(db)
 
 	logger.Info("Repositories initialized")
 
@@ -71,18 +103,88 @@ func main() {
 	commentService := comment.NewService(commentRepo)
 	hashtagService := hashtag.NewService(hashtagRepo)
 	notificationService := notification.NewService(notificationRepo)
+	searchService := search.NewService(searchRepo)
+	muteService := mute.NewService(muteRepo)
+	reportService := report.NewService(reportRepo)
+
+	postService.SetHashtagPolicy(hashtagService)
+	commentService.SetMaxTreeDepth(getEnvInt("COMMENT_TREE_DEPTH", comment.DefaultTreeDepth))
+	postService.SetPrivacyPolicy(userService)
+	commentService.SetPostLookup(postService)
+	userService.SetFollowNotifier(notificationService)
+	commentService.SetBlockPolicy(userService)
+	notificationService.SetBlockPolicy(userService)
+	postService.SetMutedWordPolicy(muteService)
+	commentService.SetMutedWordPolicy(muteService)
+	postService.SetSanctionPolicy(userService)
+	commentService.SetSanctionPolicy(userService)
+	postService.SetPermissionPolicy(userService)
+	commentService.SetPermissionPolicy(userService)
+	reportService.SetEnforcement(postService, commentService, userService)
+	reportService.SetNotifier(notificationService)
+
+	editWindows, err := revision.ParseEditWindows(getEnv("EDIT_WINDOWS", ""))
+	if err != nil {
+		logger.Fatal("Failed to parse edit windows: %v", err)
+	}
+	postService.SetEditWindows(editWindows)
+	commentService.SetEditWindows(editWindows)
+
+	reactionKinds, err := post.ParseReactionKinds(getEnv("REACTION_KINDS", ""))
+	if err != nil {
+		logger.Fatal("Failed to parse reaction kinds: %v", err)
+	}
+	postService.SetReactionKinds(reactionKinds)
+
+	reconcileService := reconcile.NewService(reconcileRepo, reactionKinds)
 
 	logObserver := notification.NewLogObserver()
 	notificationService.RegisterObserver(logObserver)
 
 	logger.Info("Services initialized")
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go runPeriodic(jobCtx, "related hashtags", getEnvDuration("RELATED_HASHTAGS_INTERVAL", 15*time.Minute), hashtagService.RefreshRelatedHashtags)
+	go runPeriodic(jobCtx, "counter reconciliation", getEnvDuration("RECONCILE_INTERVAL", 6*time.Hour), func(ctx context.Context) error {
+		report, err := reconcileService.Reconcile(ctx, reconcile.Options{
+			BatchSize: getEnvInt("RECONCILE_BATCH_SIZE", reconcile.DefaultBatchSize),
+		})
+		if err != nil {
+			return err
+		}
+		if report.Found > 0 {
+			logger.Warning("Counter reconciliation fixed %d drifted counters: %v", report.Fixed, report.ByCounter)
+		}
+		return nil
+	})
+	go runPeriodic(jobCtx, "sanction expiry", getEnvDuration("SANCTION_EXPIRY_INTERVAL", time.Minute), func(ctx context.Context) error {
+		ended, err := userService.ExpireSanctions(ctx)
+		if err != nil {
+			return err
+		}
+		if ended > 0 {
+			logger.Info("Ended %d expired user sanctions", ended)
+		}
+		return nil
+	})
+	go runPeriodic(jobCtx, "muted word expiry", getEnvDuration("MUTED_WORDS_PURGE_INTERVAL", time.Hour), func(ctx context.Context) error {
+		_, err := muteService.PurgeExpired(ctx)
+		return err
+	})
+
+	logger.Info("Background jobs started")
+
 	apiFacade := api.NewFacade(
 		userService,
 		postService,
 		commentService,
 		hashtagService,
 		notificationService,
+		searchService,
+		muteService,
+		reportService,
 	)
 
 	logger.Info("API facade initialized")
@@ -119,6 +221,8 @@ func main() {
 
 	logger.Info("Server shutting down...")
 
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -135,3 +239,69 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func loadOrCreateSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if secret := strings.TrimSpace(string(data)); secret != "" {
+			return secret, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(key)
+	if err := os.WriteFile(path, []byte(secret+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Warning("Invalid value for %s: %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		logger.Warning("Invalid value for %s: %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func runPeriodic(ctx context.Context, name string, interval time.Duration, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Job %s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"socialmediafeed/internal/infrastructure/database"
+	"socialmediafeed/internal/infrastructure/repository"
+	"socialmediafeed/internal/post"
+	"socialmediafeed/internal/reconcile"
+)
+
+func main() {
+	dbPath := flag.String("db", getEnv("DB_PATH", "data/app.db"), "database file path")
+	dryRun := flag.Bool("dry-run", false, "report discrepancies without fixing them")
+	batchSize := flag.Int("batch-size", reconcile.DefaultBatchSize, "rows checked and fixed per transaction")
+	flag.Parse()
+
+	kinds, err := post.ParseReactionKinds(os.Getenv("REACTION_KINDS"))
+	if err != nil {
+		fail("Failed to parse reaction kinds: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", *dbPath)
+	if err != nil {
+		fail("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := database.RunMigrations(db); err != nil {
+		fail("Failed to run migrations: %v", err)
+	}
+
+	service := reconcile.NewService(repository.NewReconcileRepository(db), kinds)
+	report, err := service.Reconcile(context.Background(), reconcile.Options{
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		fail("Reconciliation failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fail("Failed to write report: %v", err)
+	}
+
+	if *dryRun && report.Found > 0 {
+		os.Exit(2)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
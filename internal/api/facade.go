@@ -5,8 +5,11 @@ import (
 
 	"socialmediafeed/internal/comment"
 	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/internal/mute"
 	"socialmediafeed/internal/notification"
 	"socialmediafeed/internal/post"
+	"socialmediafeed/internal/report"
+	"socialmediafeed/internal/search"
 	"socialmediafeed/internal/user"
 	"socialmediafeed/internal/web"
 )
@@ -17,6 +20,9 @@ type Facade struct {
 	commentHandler      *comment.Handler
 	hashtagHandler      *hashtag.Handler
 	notificationHandler *notification.Handler
+	searchHandler       *search.Handler
+	muteHandler         *mute.Handler
+	reportHandler       *report.Handler
 	webHandler          *web.Handler
 	authMiddleware      *web.AuthMiddleware
 }
@@ -27,6 +33,9 @@ func NewFacade(
 	commentService *comment.Service,
 	hashtagService *hashtag.Service,
 	notificationService *notification.Service,
+	searchService *search.Service,
+	muteService *mute.Service,
+	reportService *report.Service,
 ) *Facade {
 	return &Facade{
 		userHandler:         user.NewHandler(userService),
@@ -34,7 +43,10 @@ func NewFacade(
 		commentHandler:      comment.NewHandler(commentService),
 		hashtagHandler:      hashtag.NewHandler(hashtagService),
 		notificationHandler: notification.NewHandler(notificationService),
-		webHandler:          web.NewHandler(postService, userService),
+		searchHandler:       search.NewHandler(searchService),
+		muteHandler:         mute.NewHandler(muteService),
+		reportHandler:       report.NewHandler(reportService),
+		webHandler:          web.NewHandler(postService, userService, hashtagService, searchService, reportService),
 		authMiddleware:      web.NewAuthMiddleware(userService),
 	}
 }
@@ -43,24 +55,46 @@ func (f *Facade) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /health", f.healthCheck)
 
 	f.userHandler.RegisterRoutes(mux)
-	mux.HandleFunc("POST /api/posts", f.postHandler.CreatePost)
-	mux.HandleFunc("GET /api/posts/{id}", f.postHandler.GetPostByID)
-	mux.HandleFunc("PUT /api/posts/{id}", f.postHandler.UpdatePost)
-	mux.HandleFunc("DELETE /api/posts/{id}", f.postHandler.DeletePost)
-	mux.HandleFunc("GET /api/posts", f.postHandler.GetAllPosts)
-	mux.HandleFunc("GET /api/feed", f.postHandler.GetFeed)
-	mux.HandleFunc("GET /api/trending", f.postHandler.GetTrending)
-	mux.HandleFunc("GET /api/users/{authorId}/posts", f.postHandler.GetPostsByAuthor)
-	mux.HandleFunc("GET /api/hashtags/{tag}/posts", f.postHandler.GetPostsByHashtag)
-	mux.HandleFunc("POST /api/posts/{id}/filters", f.postHandler.ApplyFilters)
+	f.userHandler.RegisterAuthenticatedRoutes(mux, f.authMiddleware.OptionalAuth)
+	mux.HandleFunc("POST /api/posts", f.authMiddleware.OptionalAuth(f.postHandler.CreatePost))
+	mux.HandleFunc("GET /api/posts/{id}", f.authMiddleware.OptionalAuth(f.postHandler.GetPostByID))
+	mux.HandleFunc("PUT /api/posts/{id}", f.authMiddleware.OptionalAuth(f.postHandler.UpdatePost))
+	mux.HandleFunc("DELETE /api/posts/{id}", f.authMiddleware.OptionalAuth(f.postHandler.DeletePost))
+	mux.HandleFunc("GET /api/posts/{id}/revisions", f.authMiddleware.OptionalAuth(f.postHandler.GetRevisions))
+	mux.HandleFunc("GET /api/posts/{id}/revisions/diff", f.authMiddleware.OptionalAuth(f.postHandler.GetRevisionDiff))
+	mux.HandleFunc("PUT /api/posts/{id}/visibility", f.authMiddleware.OptionalAuth(f.postHandler.SetVisibility))
+	mux.HandleFunc("PUT /api/posts/{id}/comments/lock", f.authMiddleware.OptionalAuth(f.postHandler.LockComments))
+	mux.HandleFunc("DELETE /api/posts/{id}/comments/lock", f.authMiddleware.OptionalAuth(f.postHandler.UnlockComments))
+	mux.HandleFunc("PUT /api/posts/{id}/comments/pin", f.authMiddleware.OptionalAuth(f.postHandler.PinComment))
+	mux.HandleFunc("DELETE /api/posts/{id}/comments/pin", f.authMiddleware.OptionalAuth(f.postHandler.UnpinComment))
+	mux.HandleFunc("GET /api/posts", f.authMiddleware.OptionalAuth(f.postHandler.GetAllPosts))
+	mux.HandleFunc("GET /api/feed", f.authMiddleware.OptionalAuth(f.postHandler.GetFeed))
+	mux.HandleFunc("GET /api/timeline", f.authMiddleware.OptionalAuth(f.postHandler.GetTimeline))
+	mux.HandleFunc("GET /api/trending", f.authMiddleware.OptionalAuth(f.postHandler.GetTrending))
+	mux.HandleFunc("GET /api/users/{authorId}/posts", f.authMiddleware.OptionalAuth(f.postHandler.GetPostsByAuthor))
+	mux.HandleFunc("GET /api/hashtags/{tag}/posts", f.authMiddleware.OptionalAuth(f.postHandler.GetPostsByHashtag))
+	mux.HandleFunc("POST /api/posts/{id}/filters", f.authMiddleware.OptionalAuth(f.postHandler.ApplyFilters))
 	mux.HandleFunc("POST /api/posts/{id}/like", f.authMiddleware.OptionalAuth(f.postHandler.LikePost))
 	mux.HandleFunc("POST /api/posts/{id}/dislike", f.authMiddleware.OptionalAuth(f.postHandler.DislikePost))
+	mux.HandleFunc("PUT /api/posts/{id}/reaction", f.authMiddleware.OptionalAuth(f.postHandler.SetReaction))
+	mux.HandleFunc("DELETE /api/posts/{id}/reaction", f.authMiddleware.OptionalAuth(f.postHandler.RemoveReaction))
+	mux.HandleFunc("GET /api/reactions/kinds", f.postHandler.GetReactionKinds)
+	mux.HandleFunc("GET /api/posts/{id}/reactions", f.authMiddleware.OptionalAuth(f.postHandler.GetReactors))
+	mux.HandleFunc("GET /api/users/{id}/likes", f.authMiddleware.OptionalAuth(f.postHandler.GetLikedPosts))
 
 	f.commentHandler.RegisterRoutes(mux)
+	f.commentHandler.RegisterAuthenticatedRoutes(mux, f.authMiddleware.OptionalAuth)
 
 	f.hashtagHandler.RegisterRoutes(mux)
+	f.hashtagHandler.RegisterAuthenticatedRoutes(mux, f.authMiddleware.OptionalAuth)
 
-	f.notificationHandler.RegisterRoutes(mux)
+	f.notificationHandler.RegisterRoutes(mux, f.authMiddleware.OptionalAuth)
+
+	f.searchHandler.RegisterRoutes(mux, f.authMiddleware.OptionalAuth)
+
+	f.muteHandler.RegisterAuthenticatedRoutes(mux, f.authMiddleware.OptionalAuth)
+
+	f.reportHandler.RegisterAuthenticatedRoutes(mux, f.authMiddleware.OptionalAuth)
 
 	f.webHandler.RegisterRoutes(mux)
 }
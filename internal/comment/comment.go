@@ -1,32 +1,43 @@
 package comment
 
 import (
+	"socialmediafeed/pkg/permission"
 	"time"
 )
 
 type Comment struct {
-	ID              int64     `json:"id" db:"id"`
-	PostID          int64     `json:"post_id" db:"post_id"`
-	UserID          int64     `json:"user_id" db:"user_id"`
-	ParentCommentID *int64    `json:"parent_comment_id,omitempty" db:"parent_comment_id"`
-	Content         string    `json:"content" db:"content"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
-
-	Author  string    `json:"author,omitempty" db:"-"`
-	Replies []Comment `json:"replies,omitempty" db:"-"`
+	ID              int64      `json:"id" db:"id"`
+	PostID          int64      `json:"post_id" db:"post_id"`
+	UserID          int64      `json:"user_id" db:"user_id"`
+	ParentCommentID *int64     `json:"parent_comment_id,omitempty" db:"parent_comment_id"`
+	Content         string     `json:"content" db:"content"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	Author         string    `json:"author,omitempty" db:"-"`
+	Likes          int       `json:"likes" db:"-"`
+	Dislikes       int       `json:"dislikes" db:"-"`
+	ViewerReaction string    `json:"viewer_reaction,omitempty" db:"-"`
+	ReplyCount     int       `json:"reply_count,omitempty" db:"-"`
+	HasMoreReplies bool      `json:"has_more_replies,omitempty" db:"-"`
+	Pinned         bool      `json:"pinned,omitempty" db:"-"`
+	Collapsed      bool      `json:"collapsed,omitempty" db:"-"`
+	RepliesCursor  string    `json:"replies_cursor,omitempty" db:"-"`
+	Score          float64   `json:"-" db:"-"`
+	Replies        []Comment `json:"replies,omitempty" db:"-"`
 }
 
 func (c *Comment) IsOwnedBy(userID int64) bool {
 	return c.UserID == userID
 }
 
-func (c *Comment) CanBeEditedBy(userID int64, role string) bool {
-	return c.IsOwnedBy(userID) || role == "admin" || role == "moderator"
+func (c *Comment) CanBeEditedBy(userID int64, permissions []string) bool {
+	return c.IsOwnedBy(userID) || permission.Has(permissions, permission.CommentEditAny)
 }
 
-func (c *Comment) CanBeDeletedBy(userID int64, role string) bool {
-	return c.IsOwnedBy(userID) || role == "admin" || role == "moderator"
+func (c *Comment) CanBeDeletedBy(userID int64, permissions []string) bool {
+	return c.IsOwnedBy(userID) || permission.Has(permissions, permission.CommentDeleteAny)
 }
 
 func (c *Comment) IsReply() bool {
@@ -45,6 +56,20 @@ func (c *Comment) IsEdited() bool {
 	return !c.UpdatedAt.Equal(c.CreatedAt)
 }
 
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+func (c *Comment) Redact() {
+	if !c.IsDeleted() {
+		return
+	}
+	c.UserID = 0
+	c.Author = ""
+	c.Content = DeletedPlaceholder
+	c.ViewerReaction = ""
+}
+
 func (c *Comment) GetAge() time.Duration {
 	return time.Since(c.CreatedAt)
 }
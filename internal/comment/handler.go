@@ -3,8 +3,12 @@ package comment
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/permission"
 	response "socialmediafeed/pkg/responce"
+	"socialmediafeed/pkg/revision"
 	"strconv"
 	"text/template"
 )
@@ -23,14 +27,24 @@ func NewHandler(service *Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("POST /api/comments", h.CreateComment)
-	mux.HandleFunc("GET /api/comments/{id}", h.GetCommentByID)
-	mux.HandleFunc("PUT /api/comments/{id}", h.UpdateComment)
-	mux.HandleFunc("DELETE /api/comments/{id}", h.DeleteComment)
-	mux.HandleFunc("GET /api/posts/{postId}/comments", h.GetPostComments)
-	mux.HandleFunc("GET /api/posts/{postId}/comments/tree", h.GetCommentTree)
 	mux.HandleFunc("GET /api/posts/{postId}/comments/count", h.GetCommentCount)
-	mux.HandleFunc("GET /api/users/{userId}/comments", h.GetUserComments)
+}
+
+func (h *Handler) RegisterAuthenticatedRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/comments/{id}", auth(h.GetCommentByID))
+	mux.HandleFunc("GET /api/posts/{postId}/comments", auth(h.GetPostComments))
+	mux.HandleFunc("GET /api/users/{userId}/comments", auth(h.GetUserComments))
+	mux.HandleFunc("GET /api/posts/{postId}/comments/tree", auth(h.GetCommentTree))
+	mux.HandleFunc("GET /api/comments/{id}/replies", auth(h.GetReplies))
+	mux.HandleFunc("POST /api/comments", auth(h.CreateComment))
+	mux.HandleFunc("PUT /api/comments/{id}", auth(h.UpdateComment))
+	mux.HandleFunc("DELETE /api/comments/{id}", auth(h.DeleteComment))
+	mux.HandleFunc("GET /api/comments/{id}/revisions", auth(h.GetRevisions))
+	mux.HandleFunc("GET /api/comments/{id}/revisions/diff", auth(h.GetRevisionDiff))
+	mux.HandleFunc("PUT /api/comments/{id}/reaction", auth(h.SetReaction))
+	mux.HandleFunc("DELETE /api/comments/{id}/reaction", auth(h.RemoveReaction))
+
+	mux.HandleFunc("GET /api/admin/comments/deletions", auth(h.GetDeletions))
 }
 
 func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
@@ -66,13 +80,24 @@ func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		response.BadRequest(w, err.Error())
+		writeCreateError(w, err)
 		return
 	}
 
 	response.Created(w, comment)
 }
 
+func writeCreateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPostNotFound), errors.Is(err, ErrParentNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrCommentsLocked), errors.Is(err, ErrBlocked), errors.Is(err, ErrAccountRestricted):
+		response.Forbidden(w, err.Error())
+	default:
+		response.BadRequest(w, err.Error())
+	}
+}
+
 func (h *Handler) GetCommentByID(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -81,9 +106,9 @@ func (h *Handler) GetCommentByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comment, err := h.service.GetCommentByID(r.Context(), id)
+	comment, err := h.service.GetCommentByID(r.Context(), id, getUserIDFromContext(r.Context()))
 	if err != nil {
-		response.NotFound(w, "Comment not found")
+		writeThreadError(w, err)
 		return
 	}
 
@@ -106,6 +131,7 @@ func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 
 	var req struct {
 		Content string `json:"content"`
+		Reason  string `json:"reason,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -114,8 +140,16 @@ func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userRole := getUserRoleFromContext(r.Context())
-	comment, err := h.service.UpdateComment(r.Context(), id, userID, req.Content, userRole)
+	comment, err := h.service.UpdateComment(r.Context(), id, userID, req.Content, userRole, req.Reason)
 	if err != nil {
+		if errors.Is(err, ErrCommentNotFound) {
+			response.NotFound(w, err.Error())
+			return
+		}
+		if errors.Is(err, revision.ErrEditWindowClosed) || errors.Is(err, ErrAccountRestricted) {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.BadRequest(w, err.Error())
 		return
 	}
@@ -137,8 +171,11 @@ func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userRole := getUserRoleFromContext(r.Context())
-	if err := h.service.DeleteComment(r.Context(), id, userID, userRole); err != nil {
+	if err := h.service.DeleteComment(r.Context(), id, userID); err != nil {
+		if errors.Is(err, ErrCommentNotFound) {
+			response.NotFound(w, err.Error())
+			return
+		}
 		response.Forbidden(w, err.Error())
 		return
 	}
@@ -154,13 +191,23 @@ func (h *Handler) GetPostComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comments, err := h.service.GetCommentsByPostID(r.Context(), postID)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetCommentsByPostID(r.Context(), postID, getUserIDFromContext(r.Context()), params)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, comments)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) GetCommentTree(w http.ResponseWriter, r *http.Request) {
@@ -171,13 +218,79 @@ func (h *Handler) GetCommentTree(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comments, err := h.service.GetCommentTree(r.Context(), postID)
+	strategy, depth, params, err := h.threadOptions(r)
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	viewerID := getUserIDFromContext(r.Context())
+	page, err := h.service.GetCommentTree(r.Context(), postID, viewerID, strategy, depth, params)
+	if err != nil {
+		writeThreadError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, comments)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) GetReplies(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	strategy, depth, params, err := h.threadOptions(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	viewerID := getUserIDFromContext(r.Context())
+	page, err := h.service.GetReplies(r.Context(), id, viewerID, strategy, depth, params)
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) threadOptions(r *http.Request) (SortStrategy, int, pagination.Params, error) {
+	strategy, err := StrategyByName(r.URL.Query().Get("sort"))
+	if err != nil {
+		return nil, 0, pagination.Params{}, err
+	}
+
+	requested := 0
+	if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+		requested, err = strconv.Atoi(depthStr)
+		if err != nil || requested <= 0 {
+			return nil, 0, pagination.Params{}, ErrInvalidDepth
+		}
+	}
+	depth := h.service.TreeDepth(requested)
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		return nil, 0, pagination.Params{}, err
+	}
+
+	return strategy, depth, params, nil
+}
+
+func writeThreadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrCommentNotFound), errors.Is(err, ErrPostNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrBlocked):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
 }
 
 func (h *Handler) GetCommentCount(w http.ResponseWriter, r *http.Request) {
@@ -205,25 +318,172 @@ func (h *Handler) GetUserComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	page, err := h.service.GetUserComments(r.Context(), userID, getUserIDFromContext(r.Context()), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
 	}
 
-	comments, err := h.service.GetUserComments(r.Context(), userID, limit, offset)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) SetReaction(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		response.InternalServerError(w, err.Error())
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	var req struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	comment, err := h.service.SetReaction(r.Context(), userID, id, req.Type)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comment)
+}
+
+func (h *Handler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	comment, err := h.service.RemoveReaction(r.Context(), userID, id)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comment)
+}
+
+func (h *Handler) GetDeletions(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, comments)
+	page, err := h.service.GetDeletions(r.Context(), params)
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func requireModerator(w http.ResponseWriter, r *http.Request) bool {
+	if getUserIDFromContext(r.Context()) == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return false
+	}
+
+	if !permission.Has(permission.FromContext(r.Context()), permission.CommentDeletionsView) {
+		response.Forbidden(w, "Permission "+string(permission.CommentDeletionsView)+" required")
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	history, err := h.service.GetRevisions(r.Context(), id, getUserIDFromContext(r.Context()))
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, history)
+}
+
+func (h *Handler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid comment ID")
+		return
+	}
+
+	from, to, err := revision.RangeFromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, getUserIDFromContext(r.Context()), from, to)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, diff)
+}
+
+func writeRevisionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrCommentNotFound), errors.Is(err, ErrPostNotFound), errors.Is(err, revision.ErrVersionNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrBlocked):
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func writeReactionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrCommentNotFound), errors.Is(err, ErrPostNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrBlocked), errors.Is(err, ErrAccountRestricted):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrInvalidReaction):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
 }
 
 func getUserIDFromContext(ctx context.Context) int64 {
@@ -0,0 +1,93 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"socialmediafeed/internal/post"
+)
+
+var (
+	ErrPostNotFound    = errors.New("post not found")
+	ErrPostNotVisible  = errors.New("you do not have access to this post")
+	ErrCommentsLocked  = errors.New("comments are locked on this post")
+	ErrParentNotFound  = errors.New("parent comment not found")
+	ErrParentNotOnPost = errors.New("parent comment does not belong to this post")
+	ErrBlocked         = errors.New("you cannot interact with this user")
+)
+
+type PostLookup interface {
+	GetPostByID(ctx context.Context, postID, viewerID int64) (*post.Post, error)
+}
+
+type BlockPolicy interface {
+	IsBlocked(ctx context.Context, userID, otherID int64) (bool, error)
+}
+
+func (s *Service) SetPostLookup(lookup PostLookup) {
+	s.posts = lookup
+}
+
+func (s *Service) SetBlockPolicy(policy BlockPolicy) {
+	s.blocks = policy
+}
+
+func (s *Service) checkNotBlocked(ctx context.Context, userID, otherID int64) error {
+	if s.blocks == nil {
+		return nil
+	}
+
+	blocked, err := s.blocks.IsBlocked(ctx, userID, otherID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+func (s *Service) findPost(ctx context.Context, postID, viewerID int64) (*post.Post, error) {
+	if s.posts == nil {
+		return nil, nil
+	}
+
+	p, err := s.posts.GetPostByID(ctx, postID, viewerID)
+	switch {
+	case errors.Is(err, post.ErrPostNotFound):
+		return nil, ErrPostNotFound
+	case errors.Is(err, post.ErrPostNotVisible):
+		return nil, ErrPostNotVisible
+	case err != nil:
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Service) findVisibleComment(ctx context.Context, id, viewerID int64) (*Comment, error) {
+	comment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if _, err := s.findPost(ctx, comment.PostID, viewerID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotBlocked(ctx, viewerID, comment.UserID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *Service) checkCommentable(ctx context.Context, postID, userID int64) error {
+	p, err := s.findPost(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if p != nil && p.CommentsLocked {
+		return ErrCommentsLocked
+	}
+	return nil
+}
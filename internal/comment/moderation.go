@@ -0,0 +1,19 @@
+package comment
+
+import "time"
+
+const DeletedPlaceholder = "[deleted]"
+
+type Deletion struct {
+	ID              int64     `json:"id"`
+	CommentID       int64     `json:"comment_id"`
+	PostID          int64     `json:"post_id"`
+	ParentCommentID *int64    `json:"parent_comment_id,omitempty"`
+	AuthorID        int64     `json:"author_id"`
+	Author          string    `json:"author"`
+	Content         string    `json:"content"`
+	DeletedBy       int64     `json:"deleted_by"`
+	DeletedByName   string    `json:"deleted_by_username"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	Purged          bool      `json:"purged"`
+}
@@ -0,0 +1,56 @@
+package comment
+
+import (
+	"context"
+	"socialmediafeed/internal/mute"
+)
+
+type MutedWordPolicy interface {
+	MatcherFor(ctx context.Context, userID int64) (*mute.Matcher, error)
+}
+
+func (s *Service) SetMutedWordPolicy(policy MutedWordPolicy) {
+	s.muted = policy
+}
+
+func (c *Comment) Collapse() {
+	c.Content = mute.Placeholder
+	c.Collapsed = true
+}
+
+func (s *Service) applyMutedWords(ctx context.Context, viewerID int64, level, descendants []Comment) ([]Comment, []Comment, error) {
+	if s.muted == nil || viewerID == 0 {
+		return level, descendants, nil
+	}
+
+	matcher, err := s.muted.MatcherFor(ctx, viewerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if matcher.Empty() {
+		return level, descendants, nil
+	}
+
+	filter := func(comments []Comment) []Comment {
+		visible := make([]Comment, 0, len(comments))
+		for _, c := range comments {
+			if c.IsOwnedBy(viewerID) || c.IsDeleted() {
+				visible = append(visible, c)
+				continue
+			}
+
+			action, matched := matcher.Match(c.Content, nil)
+			switch {
+			case !matched:
+			case action == mute.ActionHide:
+				continue
+			default:
+				c.Collapse()
+			}
+			visible = append(visible, c)
+		}
+		return visible
+	}
+
+	return filter(level), filter(descendants), nil
+}
@@ -0,0 +1,16 @@
+package comment
+
+import (
+	"context"
+	"socialmediafeed/pkg/policy"
+)
+
+type PermissionPolicy = policy.Permissions
+
+func (s *Service) SetPermissionPolicy(permissions PermissionPolicy) {
+	s.permissions = permissions
+}
+
+func (s *Service) permissionsFor(ctx context.Context, userID int64) ([]string, error) {
+	return policy.PermissionsFor(ctx, s.permissions, userID)
+}
@@ -0,0 +1,24 @@
+package comment
+
+import "errors"
+
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrInvalidReaction = errors.New("invalid reaction type, expected like or dislike")
+	ErrInvalidSort     = errors.New("invalid sort, expected top, new, old or controversial")
+	ErrInvalidDepth    = errors.New("invalid depth, expected a positive number")
+)
+
+type ReactionCounts struct {
+	Likes    int
+	Dislikes int
+}
+
+func ValidReaction(reactionType string) bool {
+	return reactionType == ReactionLike || reactionType == ReactionDislike
+}
@@ -1,15 +1,28 @@
 package comment
 
-import "context"
+import (
+	"context"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
+)
 
 type Repository interface {
 	Create(ctx context.Context, comment *Comment) error
 	FindByID(ctx context.Context, id int64) (*Comment, error)
-	FindByPostID(ctx context.Context, postID int64) ([]Comment, error)
 	FindReplies(ctx context.Context, commentID int64) ([]Comment, error)
-	FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]Comment, error)
-	Update(ctx context.Context, comment *Comment) error
-	Delete(ctx context.Context, id int64) error
+	FindPageByPostID(ctx context.Context, postID, viewerID int64, cursor *pagination.Cursor, limit int) ([]Comment, error)
+	FindRootPage(ctx context.Context, postID, viewerID int64, sort string, cursor *pagination.Cursor, limit int) ([]Comment, error)
+	FindReplyPage(ctx context.Context, parentID, viewerID int64, sort string, cursor *pagination.Cursor, limit int) ([]Comment, error)
+	FindDescendants(ctx context.Context, parentIDs []int64, viewerID int64, sort string, depth, perParent int) ([]Comment, error)
+	FindByUserID(ctx context.Context, userID, viewerID int64, cursor *pagination.Cursor, limit int) ([]Comment, error)
+	Update(ctx context.Context, comment *Comment, prior revision.Revision) error
+	FindRevisions(ctx context.Context, commentID int64) ([]revision.Revision, error)
+	SoftDelete(ctx context.Context, comment *Comment, deletedBy int64) error
+	FindDeletions(ctx context.Context, cursor *pagination.Cursor, limit int) ([]Deletion, error)
 	CountByPostID(ctx context.Context, postID int64) (int, error)
 	CountByUserID(ctx context.Context, userID int64) (int, error)
+	SetReaction(ctx context.Context, userID, commentID int64, reactionType string) error
+	RemoveReaction(ctx context.Context, userID, commentID int64) error
+	GetReactionCounts(ctx context.Context, commentIDs []int64) (map[int64]ReactionCounts, error)
+	GetUserReactions(ctx context.Context, userID int64, commentIDs []int64) (map[int64]string, error)
 }
@@ -2,20 +2,50 @@ package comment
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
 	"time"
 )
 
+const (
+	DefaultTreeDepth  = 3
+	RepliesPerComment = 5
+)
+
 type Service struct {
-	repo Repository
+	repo         Repository
+	posts        PostLookup
+	blocks       BlockPolicy
+	muted        MutedWordPolicy
+	sanctions    SanctionPolicy
+	permissions  PermissionPolicy
+	maxTreeDepth int
+	windows      revision.EditWindows
 }
 
 func NewService(repo Repository) *Service {
 	return &Service{
-		repo: repo,
+		repo:         repo,
+		maxTreeDepth: DefaultTreeDepth,
 	}
 }
 
+func (s *Service) SetMaxTreeDepth(depth int) {
+	if depth > 0 {
+		s.maxTreeDepth = depth
+	}
+}
+
+func (s *Service) SetEditWindows(windows revision.EditWindows) {
+	s.windows = windows
+}
+
+func (s *Service) MaxTreeDepth() int {
+	return s.maxTreeDepth
+}
+
 func (s *Service) CreateComment(ctx context.Context, postID, userID int64, content string) (*Comment, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -24,6 +54,14 @@ func (s *Service) CreateComment(ctx context.Context, postID, userID int64, conte
 		return nil, fmt.Errorf("comment content cannot be empty")
 	}
 
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCommentable(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
 	comment := NewComment(postID, userID, content)
 
 	err := s.repo.Create(ctx, comment)
@@ -42,16 +80,28 @@ func (s *Service) CreateReply(ctx context.Context, postID, userID, parentComment
 		return nil, fmt.Errorf("reply content cannot be empty")
 	}
 
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCommentable(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
 	parentComment, err := s.repo.FindByID(ctx, parentCommentID)
 	if err != nil {
 		return nil, err
 	}
-	if parentComment == nil {
-		return nil, fmt.Errorf("parent comment not found")
+	if parentComment == nil || parentComment.IsDeleted() {
+		return nil, ErrParentNotFound
 	}
 
 	if parentComment.PostID != postID {
-		return nil, fmt.Errorf("parent comment does not belong to this post")
+		return nil, ErrParentNotOnPost
+	}
+
+	if err := s.checkNotBlocked(ctx, userID, parentComment.UserID); err != nil {
+		return nil, err
 	}
 
 	reply := NewReply(postID, userID, parentCommentID, content)
@@ -64,68 +114,315 @@ func (s *Service) CreateReply(ctx context.Context, postID, userID, parentComment
 	return reply, nil
 }
 
-func (s *Service) GetCommentByID(ctx context.Context, id int64) (*Comment, error) {
+func (s *Service) GetCommentByID(ctx context.Context, id, viewerID int64) (*Comment, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	comment, err := s.repo.FindByID(ctx, id)
+	comment, err := s.findVisibleComment(ctx, id, viewerID)
 	if err != nil {
 		return nil, err
 	}
 
-	if comment == nil {
-		return nil, fmt.Errorf("comment not found")
-	}
+	comment.Redact()
 
 	return comment, nil
 }
 
-func (s *Service) GetCommentsByPostID(ctx context.Context, postID int64) ([]Comment, error) {
+func (s *Service) GetCommentsByPostID(ctx context.Context, postID, viewerID int64, params pagination.Params) (pagination.Page[Comment], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindByPostID(ctx, postID)
+	comments, err := s.repo.FindPageByPostID(ctx, postID, viewerID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+	redactDeleted(comments)
+
+	return pagination.NewPage(comments, params.Limit, commentTimeCursor), nil
+}
+
+func (s *Service) GetCommentTree(ctx context.Context, postID, viewerID int64, strategy SortStrategy, depth int, params pagination.Params) (pagination.Page[Comment], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	pinned, err := s.findPinned(ctx, postID, viewerID)
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	cursorFn, err := threadCursor(strategy, params.Cursor)
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	roots, err := s.repo.FindRootPage(ctx, postID, viewerID, strategy.Name(), params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	page := pagination.NewPage(roots, params.Limit, cursorFn)
+	if pinned != nil {
+		page.Items = withoutComment(page.Items, pinned.ID)
+		if params.Cursor == nil {
+			page.Items = append([]Comment{*pinned}, page.Items...)
+		}
+	}
+
+	page.Items, err = s.expandThread(ctx, page.Items, viewerID, strategy, cursorFn, depth)
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	for i := range page.Items {
+		if page.Items[i].Pinned {
+			page.Items = append(append([]Comment{page.Items[i]}, page.Items[:i]...), page.Items[i+1:]...)
+			break
+		}
+	}
+	return page, nil
+}
+
+func (s *Service) findPinned(ctx context.Context, postID, viewerID int64) (*Comment, error) {
+	p, err := s.findPost(ctx, postID, viewerID)
+	if err != nil || p == nil || p.PinnedCommentID == nil {
+		return nil, err
+	}
+
+	pinned, err := s.repo.FindByID(ctx, *p.PinnedCommentID)
+	if err != nil {
+		return nil, err
+	}
+	if pinned == nil || pinned.IsDeleted() || pinned.PostID != postID || pinned.IsReply() {
+		return nil, nil
+	}
+	if err := s.checkNotBlocked(ctx, viewerID, pinned.UserID); err != nil {
+		if errors.Is(err, ErrBlocked) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	pinned.Pinned = true
+	return pinned, nil
+}
+
+func withoutComment(comments []Comment, id int64) []Comment {
+	filtered := make([]Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.ID != id {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
 }
 
-func (s *Service) GetCommentTree(ctx context.Context, postID int64) ([]Comment, error) {
+func (s *Service) GetReplies(ctx context.Context, commentID, viewerID int64, strategy SortStrategy, depth int, params pagination.Params) (pagination.Page[Comment], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	allComments, err := s.repo.FindByPostID(ctx, postID)
+	if _, err := s.findVisibleComment(ctx, commentID, viewerID); err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	cursorFn, err := threadCursor(strategy, params.Cursor)
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	replies, err := s.repo.FindReplyPage(ctx, commentID, viewerID, strategy.Name(), params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	page := pagination.NewPage(replies, params.Limit, cursorFn)
+	page.Items, err = s.expandThread(ctx, page.Items, viewerID, strategy, cursorFn, depth)
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+	return page, nil
+}
+
+func (s *Service) TreeDepth(requested int) int {
+	if requested <= 0 || requested > s.maxTreeDepth {
+		return s.maxTreeDepth
+	}
+	return requested
+}
+
+func (s *Service) expandThread(ctx context.Context, level []Comment, viewerID int64, strategy SortStrategy, cursorFn func(Comment) *pagination.Cursor, depth int) ([]Comment, error) {
+	ids := make([]int64, len(level))
+	for i, c := range level {
+		ids[i] = c.ID
+	}
+
+	descendants, err := s.repo.FindDescendants(ctx, ids, viewerID, strategy.Name(), depth-1, RepliesPerComment)
 	if err != nil {
 		return nil, err
 	}
 
-	commentMap := make(map[int64]*Comment)
-	var rootComments []Comment
+	all := append(append([]Comment{}, level...), descendants...)
+	if err := s.AttachReactions(ctx, viewerID, all); err != nil {
+		return nil, err
+	}
+	redactDeleted(all)
 
-	for i := range allComments {
-		commentMap[allComments[i].ID] = &allComments[i]
-		allComments[i].Replies = []Comment{}
+	level, descendants, err = s.applyMutedWords(ctx, viewerID, all[:len(level)], all[len(level):])
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(level, descendants, strategy, cursorFn), nil
+}
+
+func threadCursor(strategy SortStrategy, cursor *pagination.Cursor) (func(Comment) *pagination.Cursor, error) {
+	switch strategy.Name() {
+	case SortOld, SortNew:
+		return commentTimeCursor, nil
+	}
+
+	asOf := time.Now()
+	if cursor != nil {
+		var err error
+		if _, asOf, err = cursor.Score(strategy.Name()); err != nil {
+			return nil, err
+		}
+	}
+	return func(c Comment) *pagination.Cursor {
+		return pagination.NewScoreCursor(strategy.Name(), c.Score, asOf, c.ID)
+	}, nil
+}
+
+func buildTree(level, descendants []Comment, strategy SortStrategy, cursorFn func(Comment) *pagination.Cursor) []Comment {
+	children := make(map[int64][]Comment)
+	for _, c := range descendants {
+		children[*c.ParentCommentID] = append(children[*c.ParentCommentID], c)
 	}
 
-	for i := range allComments {
-		if allComments[i].ParentCommentID == nil {
-			rootComments = append(rootComments, allComments[i])
-		} else {
-			parent := commentMap[*allComments[i].ParentCommentID]
-			if parent != nil {
-				parent.Replies = append(parent.Replies, allComments[i])
+	var attach func(level []Comment) []Comment
+	attach = func(level []Comment) []Comment {
+		level = strategy.Sort(level)
+		for i := range level {
+			level[i].Replies = attach(children[level[i].ID])
+			level[i].HasMoreReplies = level[i].ReplyCount > len(level[i].Replies)
+			if level[i].HasMoreReplies && len(level[i].Replies) > 0 {
+				level[i].RepliesCursor = cursorFn(level[i].Replies[len(level[i].Replies)-1]).Encode()
 			}
 		}
+		return level
 	}
 
-	return rootComments, nil
+	return attach(level)
 }
 
-func (s *Service) GetUserComments(ctx context.Context, userID int64, limit, offset int) ([]Comment, error) {
+func redactDeleted(comments []Comment) {
+	for i := range comments {
+		comments[i].Redact()
+	}
+}
+
+func (s *Service) AttachReactions(ctx context.Context, viewerID int64, comments []Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+
+	counts, err := s.repo.GetReactionCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	viewerReactions := make(map[int64]string)
+	if viewerID != 0 {
+		viewerReactions, err = s.repo.GetUserReactions(ctx, viewerID, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range comments {
+		comments[i].Likes = counts[comments[i].ID].Likes
+		comments[i].Dislikes = counts[comments[i].ID].Dislikes
+		comments[i].ViewerReaction = viewerReactions[comments[i].ID]
+	}
+
+	return nil
+}
+
+func (s *Service) SetReaction(ctx context.Context, userID, commentID int64, reactionType string) (*Comment, error) {
+	if !ValidReaction(reactionType) {
+		return nil, ErrInvalidReaction
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findVisibleComment(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentNotFound
+	}
+
+	if err := s.repo.SetReaction(ctx, userID, commentID, reactionType); err != nil {
+		return nil, err
+	}
+
+	comments := []Comment{*comment}
+	if err := s.AttachReactions(ctx, userID, comments); err != nil {
+		return nil, err
+	}
+	return &comments[0], nil
+}
+
+func (s *Service) RemoveReaction(ctx context.Context, userID, commentID int64) (*Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findVisibleComment(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentNotFound
+	}
+
+	if err := s.repo.RemoveReaction(ctx, userID, commentID); err != nil {
+		return nil, err
+	}
+
+	comments := []Comment{*comment}
+	if err := s.AttachReactions(ctx, userID, comments); err != nil {
+		return nil, err
+	}
+	return &comments[0], nil
+}
+
+func (s *Service) GetUserComments(ctx context.Context, userID, viewerID int64, params pagination.Params) (pagination.Page[Comment], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindByUserID(ctx, userID, limit, offset)
+	comments, err := s.repo.FindByUserID(ctx, userID, viewerID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Comment]{}, err
+	}
+
+	return pagination.NewPage(comments, params.Limit, commentTimeCursor), nil
 }
 
-func (s *Service) UpdateComment(ctx context.Context, id, userID int64, content string, userRole string) (*Comment, error) {
+func (s *Service) UpdateComment(ctx context.Context, id, userID int64, content, userRole, reason string) (*Comment, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -133,22 +430,46 @@ func (s *Service) UpdateComment(ctx context.Context, id, userID int64, content s
 	if err != nil {
 		return nil, err
 	}
-	if comment == nil {
-		return nil, fmt.Errorf("comment not found")
+	if comment == nil || comment.IsDeleted() {
+		return nil, ErrCommentNotFound
 	}
 
-	if !comment.CanBeEditedBy(userID, userRole) {
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !comment.CanBeEditedBy(userID, permissions) {
 		return nil, fmt.Errorf("unauthorized to edit this comment")
 	}
 
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	if content == "" {
 		return nil, fmt.Errorf("comment content cannot be empty")
 	}
 
+	now := time.Now()
+	if !s.windows.Allows(userRole, comment.CreatedAt, now) {
+		return nil, revision.ErrEditWindowClosed
+	}
+
+	if content == comment.Content {
+		return comment, nil
+	}
+
+	prior := revision.Revision{
+		Content:  comment.Content,
+		EditorID: userID,
+		Reason:   reason,
+		EditedAt: now,
+	}
+
 	comment.Content = content
-	comment.UpdatedAt = time.Now()
+	comment.UpdatedAt = now
 
-	err = s.repo.Update(ctx, comment)
+	err = s.repo.Update(ctx, comment, prior)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +477,35 @@ func (s *Service) UpdateComment(ctx context.Context, id, userID int64, content s
 	return comment, nil
 }
 
-func (s *Service) DeleteComment(ctx context.Context, id, userID int64, userRole string) error {
+func (s *Service) GetRevisions(ctx context.Context, commentID, viewerID int64) (revision.History, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	comment, err := s.findVisibleComment(ctx, commentID, viewerID)
+	if err != nil {
+		return revision.History{}, err
+	}
+	if comment.IsDeleted() {
+		return revision.History{}, ErrCommentNotFound
+	}
+
+	revisions, err := s.repo.FindRevisions(ctx, commentID)
+	if err != nil {
+		return revision.History{}, err
+	}
+
+	return revision.NewHistory(revisions, comment.Content), nil
+}
+
+func (s *Service) DiffRevisions(ctx context.Context, commentID, viewerID int64, from, to int) (revision.Diff, error) {
+	history, err := s.GetRevisions(ctx, commentID, viewerID)
+	if err != nil {
+		return revision.Diff{}, err
+	}
+	return history.Diff(from, to)
+}
+
+func (s *Service) DeleteComment(ctx context.Context, id, userID int64) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -164,15 +513,33 @@ func (s *Service) DeleteComment(ctx context.Context, id, userID int64, userRole
 	if err != nil {
 		return err
 	}
-	if comment == nil {
-		return fmt.Errorf("comment not found")
+	if comment == nil || comment.IsDeleted() {
+		return ErrCommentNotFound
 	}
 
-	if !comment.CanBeDeletedBy(userID, userRole) {
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !comment.CanBeDeletedBy(userID, permissions) {
 		return fmt.Errorf("unauthorized to delete this comment")
 	}
 
-	return s.repo.Delete(ctx, id)
+	return s.repo.SoftDelete(ctx, comment, userID)
+}
+
+func (s *Service) GetDeletions(ctx context.Context, params pagination.Params) (pagination.Page[Deletion], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deletions, err := s.repo.FindDeletions(ctx, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Deletion]{}, err
+	}
+
+	return pagination.NewPage(deletions, params.Limit, func(d Deletion) *pagination.Cursor {
+		return pagination.NewTimeCursor(d.DeletedAt, d.ID)
+	}), nil
 }
 
 func (s *Service) GetCommentCount(ctx context.Context, postID int64) (int, error) {
@@ -188,3 +555,7 @@ func (s *Service) GetUserCommentCount(ctx context.Context, userID int64) (int, e
 
 	return s.repo.CountByUserID(ctx, userID)
 }
+
+func commentTimeCursor(c Comment) *pagination.Cursor {
+	return pagination.NewTimeCursor(c.CreatedAt, c.ID)
+}
@@ -0,0 +1,151 @@
+package comment
+
+import (
+	"sort"
+)
+
+const (
+	SortOld           = "old"
+	SortNew           = "new"
+	SortTop           = "top"
+	SortControversial = "controversial"
+)
+
+type SortStrategy interface {
+	Sort(comments []Comment) []Comment
+	Name() string
+}
+
+type OldestStrategy struct{}
+
+func NewOldestStrategy() SortStrategy {
+	return &OldestStrategy{}
+}
+
+func (s *OldestStrategy) Sort(comments []Comment) []Comment {
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+func (s *OldestStrategy) Name() string {
+	return SortOld
+}
+
+type NewestStrategy struct{}
+
+func NewNewestStrategy() SortStrategy {
+	return &NewestStrategy{}
+}
+
+func (s *NewestStrategy) Sort(comments []Comment) []Comment {
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	return sorted
+}
+
+func (s *NewestStrategy) Name() string {
+	return SortNew
+}
+
+type TopStrategy struct{}
+
+func NewTopStrategy() SortStrategy {
+	return &TopStrategy{}
+}
+
+func (s *TopStrategy) Sort(comments []Comment) []Comment {
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		scoreI := sorted[i].Likes - sorted[i].Dislikes
+		scoreJ := sorted[j].Likes - sorted[j].Dislikes
+		if scoreI != scoreJ {
+			return scoreI > scoreJ
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+func (s *TopStrategy) Name() string {
+	return SortTop
+}
+
+type ControversialStrategy struct{}
+
+func NewControversialStrategy() SortStrategy {
+	return &ControversialStrategy{}
+}
+
+func (s *ControversialStrategy) Sort(comments []Comment) []Comment {
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		controversyI := s.calculateControversy(sorted[i])
+		controversyJ := s.calculateControversy(sorted[j])
+		if controversyI != controversyJ {
+			return controversyI > controversyJ
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+func (s *ControversialStrategy) calculateControversy(c Comment) float64 {
+	total := c.Likes + c.Dislikes
+	if total == 0 {
+		return 0
+	}
+
+	ratio := float64(c.Likes) / float64(total)
+
+	if ratio < 0.3 || ratio > 0.7 {
+		return 0
+	}
+
+	distance := ratio - 0.5
+	if distance < 0 {
+		distance = -distance
+	}
+	return float64(total) * (1 - distance*2)
+}
+
+func (s *ControversialStrategy) Name() string {
+	return SortControversial
+}
+
+func StrategyByName(name string) (SortStrategy, error) {
+	switch name {
+	case "", SortOld, "oldest":
+		return NewOldestStrategy(), nil
+	case SortNew, "newest":
+		return NewNewestStrategy(), nil
+	case SortTop:
+		return NewTopStrategy(), nil
+	case SortControversial:
+		return NewControversialStrategy(), nil
+	default:
+		return nil, ErrInvalidSort
+	}
+}
@@ -1,7 +1,12 @@
 package hashtag
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/permission"
 	"socialmediafeed/pkg/responce"
 	"strconv"
 	"text/template"
@@ -25,7 +30,27 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/hashtags/trending", h.GetTrending)
 	mux.HandleFunc("GET /api/hashtags/popular", h.GetPopular)
 	mux.HandleFunc("GET /api/hashtags/search", h.SearchHashtags)
+	mux.HandleFunc("GET /api/hashtags/autocomplete", h.AutocompleteHashtags)
 	mux.HandleFunc("GET /api/hashtags/{tag}", h.GetHashtagByTag)
+	mux.HandleFunc("GET /api/hashtags/{tag}/stats", h.GetHashtagStats)
+	mux.HandleFunc("GET /api/hashtags/{tag}/related", h.GetRelatedHashtags)
+	mux.HandleFunc("GET /api/users/{id}/hashtags", h.GetFollowedHashtags)
+}
+
+func (h *Handler) RegisterAuthenticatedRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("POST /api/hashtags/{tag}/follow", auth(h.FollowHashtag))
+	mux.HandleFunc("DELETE /api/hashtags/{tag}/follow", auth(h.UnfollowHashtag))
+
+	mux.HandleFunc("POST /api/admin/hashtags/merge", auth(h.MergeHashtags))
+	mux.HandleFunc("POST /api/admin/hashtags/{tag}/rename", auth(h.RenameHashtag))
+	mux.HandleFunc("GET /api/admin/hashtags/aliases", auth(h.GetAliases))
+	mux.HandleFunc("POST /api/admin/hashtags/aliases", auth(h.CreateAlias))
+	mux.HandleFunc("DELETE /api/admin/hashtags/aliases/{alias}", auth(h.DeleteAlias))
+	mux.HandleFunc("GET /api/admin/hashtags/blocks", auth(h.GetBlocks))
+	mux.HandleFunc("POST /api/admin/hashtags/blocks", auth(h.BlockHashtag))
+	mux.HandleFunc("DELETE /api/admin/hashtags/blocks/{tag}", auth(h.UnblockHashtag))
+	mux.HandleFunc("GET /api/admin/hashtags/flagged", auth(h.GetFlaggedPosts))
+	mux.HandleFunc("GET /api/admin/hashtags/audit", auth(h.GetAuditLog))
 }
 
 func (h *Handler) GetAllHashtags(w http.ResponseWriter, r *http.Request) {
@@ -39,13 +64,19 @@ func (h *Handler) GetAllHashtags(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetTrending(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	hashtags, err := h.service.GetTrendingHashtags(r.Context(), limit)
+	window, err := ParseWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	hashtags, err := h.service.GetTrendingHashtags(r.Context(), window, limit)
 	if err != nil {
 		response.InternalServerError(w, err.Error())
 		return
@@ -55,10 +86,10 @@ func (h *Handler) GetTrending(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetPopular(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
 	hashtags, err := h.service.GetPopularHashtags(r.Context(), limit)
@@ -77,10 +108,10 @@ func (h *Handler) SearchHashtags(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	limit, err := pagination.LimitFromRequest(r, 20)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
 	hashtags, err := h.service.SearchHashtags(r.Context(), query, limit)
@@ -92,6 +123,28 @@ func (h *Handler) SearchHashtags(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, hashtags)
 }
 
+func (h *Handler) AutocompleteHashtags(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	if prefix == "" {
+		response.BadRequest(w, "Query parameter 'prefix' is required")
+		return
+	}
+
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	hashtags, err := h.service.AutocompleteHashtags(r.Context(), prefix, limit)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hashtags)
+}
+
 func (h *Handler) GetHashtagByTag(w http.ResponseWriter, r *http.Request) {
 	tag := r.PathValue("tag")
 	if tag == "" {
@@ -107,3 +160,345 @@ func (h *Handler) GetHashtagByTag(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, http.StatusOK, hashtag)
 }
+
+func (h *Handler) GetHashtagStats(w http.ResponseWriter, r *http.Request) {
+	tag := r.PathValue("tag")
+	if tag == "" {
+		response.BadRequest(w, "Tag is required")
+		return
+	}
+
+	window, err := ParseWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetHashtagStats(r.Context(), tag, window)
+	if err != nil {
+		if errors.Is(err, ErrHashtagNotFound) {
+			response.NotFound(w, "Hashtag not found")
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) GetRelatedHashtags(w http.ResponseWriter, r *http.Request) {
+	tag := r.PathValue("tag")
+	if tag == "" {
+		response.BadRequest(w, "Tag is required")
+		return
+	}
+
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	related, err := h.service.GetRelatedHashtags(r.Context(), tag, limit)
+	if err != nil {
+		if errors.Is(err, ErrHashtagNotFound) {
+			response.NotFound(w, "Hashtag not found")
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, related)
+}
+
+func (h *Handler) FollowHashtag(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	hashtag, err := h.service.FollowHashtag(r.Context(), userID, r.PathValue("tag"))
+	if err != nil {
+		if errors.Is(err, ErrTagBlocked) {
+			response.Forbidden(w, err.Error())
+			return
+		}
+		writeModerationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hashtag)
+}
+
+func (h *Handler) UnfollowHashtag(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	if err := h.service.UnfollowHashtag(r.Context(), userID, r.PathValue("tag")); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *Handler) GetFollowedHashtags(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	hashtags, err := h.service.GetFollowedHashtags(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hashtags)
+}
+
+func (h *Handler) MergeHashtags(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagMerge)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+	if req.Source == "" || req.Target == "" {
+		response.BadRequest(w, "Source and target are required")
+		return
+	}
+
+	if err := h.service.MergeHashtags(r.Context(), req.Source, req.Target, actorID); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.Success(w, "Hashtag merged successfully")
+}
+
+func (h *Handler) RenameHashtag(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagRename)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	hashtag, err := h.service.RenameHashtag(r.Context(), r.PathValue("tag"), req.Tag, actorID)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hashtag)
+}
+
+func (h *Handler) GetAliases(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requirePermission(w, r, permission.HashtagAlias); !ok {
+		return
+	}
+
+	aliases, err := h.service.GetAliases(r.Context())
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, aliases)
+}
+
+func (h *Handler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagAlias)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Alias string `json:"alias"`
+		Tag   string `json:"tag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+	if req.Alias == "" || req.Tag == "" {
+		response.BadRequest(w, "Alias and tag are required")
+		return
+	}
+
+	if err := h.service.CreateAlias(r.Context(), req.Alias, req.Tag, actorID); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.Created(w, map[string]string{"alias": NormalizeTag(req.Alias), "tag": NormalizeTag(req.Tag)})
+}
+
+func (h *Handler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagAlias)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAlias(r.Context(), r.PathValue("alias"), actorID); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *Handler) GetBlocks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requirePermission(w, r, permission.HashtagBlock); !ok {
+		return
+	}
+
+	blocks, err := h.service.GetBlocks(r.Context())
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, blocks)
+}
+
+func (h *Handler) BlockHashtag(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagBlock)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Tag    string `json:"tag"`
+		Mode   string `json:"mode"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	mode, err := ParseBlockMode(req.Mode)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	block, err := h.service.BlockHashtag(r.Context(), req.Tag, mode, req.Reason, actorID)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.Created(w, block)
+}
+
+func (h *Handler) UnblockHashtag(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := requirePermission(w, r, permission.HashtagBlock)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnblockHashtag(r.Context(), r.PathValue("tag"), actorID); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *Handler) GetFlaggedPosts(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requirePermission(w, r, permission.HashtagModerationView); !ok {
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetFlaggedPosts(r.Context(), params)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requirePermission(w, r, permission.HashtagModerationView); !ok {
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetAuditLog(r.Context(), params)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func getUserIDFromContext(ctx context.Context) int64 {
+	if userID, ok := ctx.Value("userID").(int64); ok {
+		return userID
+	}
+	return 0
+}
+
+func requirePermission(w http.ResponseWriter, r *http.Request, p permission.Permission) (int64, bool) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return 0, false
+	}
+
+	if !permission.Has(permission.FromContext(r.Context()), p) {
+		response.Forbidden(w, "Permission "+string(p)+" required")
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func writeModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrHashtagNotFound), errors.Is(err, ErrAliasNotFound), errors.Is(err, ErrBlockNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrTagExists):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidTag), errors.Is(err, ErrSameTag), errors.Is(err, ErrInvalidMode), errors.Is(err, pagination.ErrInvalidCursor):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
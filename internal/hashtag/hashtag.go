@@ -73,3 +73,33 @@ func IsValidTag(tag string) bool {
 	}
 	return true
 }
+
+func ExtractTags(content string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && isTagChar(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isTagChar(runes[end]) {
+			end++
+		}
+
+		tag := NormalizeTag(string(runes[i+1 : end]))
+		if IsValidTag(tag) && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		i = end - 1
+	}
+
+	return tags
+}
+
+func isTagChar(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_'
+}
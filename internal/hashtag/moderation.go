@@ -0,0 +1,83 @@
+package hashtag
+
+import (
+	"errors"
+	"time"
+)
+
+type BlockMode string
+
+const (
+	BlockReject BlockMode = "reject"
+	BlockFlag   BlockMode = "flag"
+)
+
+type AuditAction string
+
+const (
+	AuditMerge       AuditAction = "merge"
+	AuditRename      AuditAction = "rename"
+	AuditAlias       AuditAction = "alias"
+	AuditRemoveAlias AuditAction = "remove_alias"
+	AuditBlock       AuditAction = "block"
+	AuditUnblock     AuditAction = "unblock"
+)
+
+var (
+	ErrHashtagNotFound = errors.New("hashtag not found")
+	ErrTagBlocked      = errors.New("hashtag is blocked")
+	ErrTagExists       = errors.New("hashtag already exists")
+	ErrInvalidTag      = errors.New("invalid hashtag format")
+	ErrInvalidMode     = errors.New("invalid block mode, expected reject or flag")
+	ErrSameTag         = errors.New("source and target hashtags must differ")
+	ErrAliasNotFound   = errors.New("alias not found")
+	ErrBlockNotFound   = errors.New("block not found")
+)
+
+type Alias struct {
+	Alias     string    `json:"alias"`
+	Tag       string    `json:"tag"`
+	HashtagID int64     `json:"hashtag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Block struct {
+	Tag       string    `json:"tag"`
+	Mode      BlockMode `json:"mode"`
+	Reason    string    `json:"reason"`
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditEntry struct {
+	ID        int64       `json:"id"`
+	Action    AuditAction `json:"action"`
+	ActorID   int64       `json:"actor_id"`
+	Tag       string      `json:"tag"`
+	TargetTag string      `json:"target_tag,omitempty"`
+	Details   string      `json:"details,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type FlaggedPost struct {
+	ID        int64     `json:"-"`
+	PostID    int64     `json:"post_id"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Resolution struct {
+	Tags    []string
+	Flagged []string
+}
+
+func ParseBlockMode(value string) (BlockMode, error) {
+	switch BlockMode(value) {
+	case "":
+		return BlockReject, nil
+	case BlockReject, BlockFlag:
+		return BlockMode(value), nil
+	default:
+		return "", ErrInvalidMode
+	}
+}
@@ -0,0 +1,24 @@
+package hashtag
+
+import (
+	"math"
+	"time"
+)
+
+const MinRelatedPairs = 2
+
+type RelatedHashtag struct {
+	Tag        string    `json:"tag"`
+	UsageCount int       `json:"usage_count"`
+	PairCount  int       `json:"pair_count"`
+	Lift       float64   `json:"lift"`
+	PMI        float64   `json:"pmi"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+func PMI(lift float64) float64 {
+	if lift <= 0 {
+		return 0
+	}
+	return math.Log2(lift)
+}
@@ -2,6 +2,7 @@ package hashtag
 
 import (
 	"context"
+	"socialmediafeed/pkg/pagination"
 	"time"
 )
 
@@ -10,13 +11,34 @@ type Repository interface {
 	FindByID(ctx context.Context, id int64) (*Hashtag, error)
 	FindByTag(ctx context.Context, tag string) (*Hashtag, error)
 	FindAll(ctx context.Context) ([]Hashtag, error)
-	FindTrending(ctx context.Context, limit int) ([]Hashtag, error)
+	FindUsageSince(ctx context.Context, activeSince, since time.Time) ([]UsageBucket, error)
+	RebuildRelated(ctx context.Context, minPairs int) (int64, error)
+	FindRelated(ctx context.Context, hashtagID int64, limit int) ([]RelatedHashtag, error)
+	FindUsageByHashtag(ctx context.Context, hashtagID int64, since time.Time) ([]UsageBucket, error)
 	FindPopular(ctx context.Context, limit int) ([]Hashtag, error)
 	Update(ctx context.Context, hashtag *Hashtag) error
 	Delete(ctx context.Context, id int64) error
 	IncrementUsage(ctx context.Context, tag string) error
 	DecrementUsage(ctx context.Context, tag string) error
 	Search(ctx context.Context, query string, limit int) ([]Hashtag, error)
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]Hashtag, error)
 	CleanupUnused(ctx context.Context, olderThan time.Duration) error
 	GetOrCreate(ctx context.Context, tag string) (*Hashtag, error)
+	Follow(ctx context.Context, userID, hashtagID int64) error
+	Unfollow(ctx context.Context, userID, hashtagID int64) error
+	IsFollowing(ctx context.Context, userID, hashtagID int64) (bool, error)
+	FindFollowedByUser(ctx context.Context, userID int64) ([]Hashtag, error)
+	Merge(ctx context.Context, sourceID, targetID, actorID int64) error
+	Rename(ctx context.Context, id int64, newTag string, actorID int64) error
+	CreateAlias(ctx context.Context, alias string, hashtagID, actorID int64) error
+	DeleteAlias(ctx context.Context, alias string, actorID int64) error
+	FindAliasTarget(ctx context.Context, alias string) (string, error)
+	FindAliases(ctx context.Context) ([]Alias, error)
+	CreateBlock(ctx context.Context, block *Block) error
+	DeleteBlock(ctx context.Context, tag string, actorID int64) error
+	FindBlock(ctx context.Context, tag string) (*Block, error)
+	FindBlocks(ctx context.Context) ([]Block, error)
+	FlagPost(ctx context.Context, postID int64, tags []string) error
+	FindFlaggedPosts(ctx context.Context, cursor *pagination.Cursor, limit int) ([]FlaggedPost, error)
+	FindAuditLog(ctx context.Context, cursor *pagination.Cursor, limit int) ([]AuditEntry, error)
 }
@@ -3,6 +3,7 @@ package hashtag
 import (
 	"context"
 	"fmt"
+	"socialmediafeed/pkg/pagination"
 	"time"
 )
 
@@ -21,7 +22,7 @@ func (s *Service) CreateHashtag(ctx context.Context, tag string) (*Hashtag, erro
 	defer cancel()
 
 	if !IsValidTag(tag) {
-		return nil, fmt.Errorf("invalid hashtag format")
+		return nil, ErrInvalidTag
 	}
 
 	normalized := NormalizeTag(tag)
@@ -31,7 +32,7 @@ func (s *Service) CreateHashtag(ctx context.Context, tag string) (*Hashtag, erro
 		return nil, err
 	}
 	if existing != nil {
-		return nil, fmt.Errorf("hashtag already exists")
+		return nil, ErrTagExists
 	}
 
 	hashtag := NewHashtag(normalized)
@@ -53,7 +54,7 @@ func (s *Service) GetHashtagByTag(ctx context.Context, tag string) (*Hashtag, er
 		return nil, err
 	}
 	if hashtag == nil {
-		return nil, fmt.Errorf("hashtag not found")
+		return nil, ErrHashtagNotFound
 	}
 
 	return hashtag, nil
@@ -64,7 +65,7 @@ func (s *Service) GetOrCreateHashtag(ctx context.Context, tag string) (*Hashtag,
 	defer cancel()
 
 	if !IsValidTag(tag) {
-		return nil, fmt.Errorf("invalid hashtag format")
+		return nil, ErrInvalidTag
 	}
 
 	normalized := NormalizeTag(tag)
@@ -78,7 +79,7 @@ func (s *Service) GetAllHashtags(ctx context.Context) ([]Hashtag, error) {
 	return s.repo.FindAll(ctx)
 }
 
-func (s *Service) GetTrendingHashtags(ctx context.Context, limit int) ([]Hashtag, error) {
+func (s *Service) GetTrendingHashtags(ctx context.Context, window Window, limit int) ([]TrendingHashtag, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -86,7 +87,101 @@ func (s *Service) GetTrendingHashtags(ctx context.Context, limit int) ([]Hashtag
 		limit = 10
 	}
 
-	return s.repo.FindTrending(ctx, limit)
+	now := time.Now()
+	buckets, err := s.repo.FindUsageSince(ctx, window.Start(now), window.BaselineStart(now))
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := RankTrending(buckets, window, now)
+	trending := make([]TrendingHashtag, 0, limit)
+	for _, t := range ranked {
+		if len(trending) == limit {
+			break
+		}
+
+		hashtag, err := s.repo.FindByID(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if hashtag == nil {
+			continue
+		}
+
+		t.Hashtag = *hashtag
+		trending = append(trending, t)
+	}
+
+	return trending, nil
+}
+
+func (s *Service) GetHashtagStats(ctx context.Context, tag string, window Window) (*UsageStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	hashtag, err := s.repo.FindByTag(ctx, NormalizeTag(tag))
+	if err != nil {
+		return nil, err
+	}
+	if hashtag == nil {
+		return nil, ErrHashtagNotFound
+	}
+
+	now := time.Now()
+	buckets, err := s.repo.FindUsageByHashtag(ctx, hashtag.ID, window.Start(now))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &UsageStats{
+		Tag:    hashtag.Tag,
+		Window: window,
+		Series: FillSeries(buckets, window, now),
+	}
+	for _, b := range stats.Series {
+		stats.Total += b.Count
+	}
+
+	return stats, nil
+}
+
+func (s *Service) GetRelatedHashtags(ctx context.Context, tag string, limit int) ([]RelatedHashtag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	hashtag, err := s.repo.FindByTag(ctx, NormalizeTag(tag))
+	if err != nil {
+		return nil, err
+	}
+	if hashtag == nil {
+		return nil, ErrHashtagNotFound
+	}
+
+	related, err := s.repo.FindRelated(ctx, hashtag.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if related == nil {
+		related = []RelatedHashtag{}
+	}
+
+	return related, nil
+}
+
+func (s *Service) RefreshRelatedHashtags(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	_, err := s.repo.RebuildRelated(ctx, MinRelatedPairs)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild related hashtags: %w", err)
+	}
+
+	return nil
 }
 
 func (s *Service) GetPopularHashtags(ctx context.Context, limit int) ([]Hashtag, error) {
@@ -112,6 +207,30 @@ func (s *Service) SearchHashtags(ctx context.Context, query string, limit int) (
 	return s.repo.Search(ctx, normalized, limit)
 }
 
+func (s *Service) AutocompleteHashtags(ctx context.Context, prefix string, limit int) ([]Hashtag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	normalized := NormalizeTag(prefix)
+	if normalized == "" {
+		return []Hashtag{}, nil
+	}
+
+	hashtags, err := s.repo.Autocomplete(ctx, normalized, limit)
+	if err != nil {
+		return nil, err
+	}
+	if hashtags == nil {
+		hashtags = []Hashtag{}
+	}
+
+	return hashtags, nil
+}
+
 func (s *Service) IncrementUsage(ctx context.Context, tag string) error {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -161,3 +280,282 @@ func (s *Service) CleanupUnusedHashtags(ctx context.Context) error {
 
 	return s.repo.CleanupUnused(ctx, 90*24*time.Hour)
 }
+
+func (s *Service) ResolveTags(ctx context.Context, tags []string) (*Resolution, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resolution := &Resolution{Tags: make([]string, 0, len(tags))}
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		normalized := NormalizeTag(tag)
+
+		canonical, err := s.repo.FindAliasTarget(ctx, normalized)
+		if err != nil {
+			return nil, err
+		}
+		if canonical == "" {
+			canonical = normalized
+		}
+
+		for _, candidate := range []string{normalized, canonical} {
+			block, err := s.repo.FindBlock(ctx, candidate)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				continue
+			}
+			if block.Mode == BlockReject {
+				return nil, fmt.Errorf("%w: #%s", ErrTagBlocked, candidate)
+			}
+			if !seen["flag:"+candidate] {
+				seen["flag:"+candidate] = true
+				resolution.Flagged = append(resolution.Flagged, candidate)
+			}
+		}
+
+		if !seen[canonical] {
+			seen[canonical] = true
+			resolution.Tags = append(resolution.Tags, canonical)
+		}
+	}
+
+	return resolution, nil
+}
+
+func (s *Service) FollowHashtag(ctx context.Context, userID int64, tag string) (*Hashtag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !IsValidTag(tag) {
+		return nil, ErrInvalidTag
+	}
+
+	resolution, err := s.ResolveTags(ctx, []string{tag})
+	if err != nil {
+		return nil, err
+	}
+
+	hashtag, err := s.repo.GetOrCreate(ctx, resolution.Tags[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Follow(ctx, userID, hashtag.ID); err != nil {
+		return nil, fmt.Errorf("failed to follow hashtag: %w", err)
+	}
+
+	return hashtag, nil
+}
+
+func (s *Service) UnfollowHashtag(ctx context.Context, userID int64, tag string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	hashtag, err := s.GetHashtagByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Unfollow(ctx, userID, hashtag.ID)
+}
+
+func (s *Service) IsFollowing(ctx context.Context, userID, hashtagID int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return s.repo.IsFollowing(ctx, userID, hashtagID)
+}
+
+func (s *Service) GetFollowedHashtags(ctx context.Context, userID int64) ([]Hashtag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	hashtags, err := s.repo.FindFollowedByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if hashtags == nil {
+		hashtags = []Hashtag{}
+	}
+	return hashtags, nil
+}
+
+func (s *Service) FlagPost(ctx context.Context, postID int64, tags []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.FlagPost(ctx, postID, tags)
+}
+
+func (s *Service) MergeHashtags(ctx context.Context, source, target string, actorID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	sourceTag, err := s.GetHashtagByTag(ctx, source)
+	if err != nil {
+		return err
+	}
+	targetTag, err := s.GetHashtagByTag(ctx, target)
+	if err != nil {
+		return err
+	}
+	if sourceTag.ID == targetTag.ID {
+		return ErrSameTag
+	}
+
+	return s.repo.Merge(ctx, sourceTag.ID, targetTag.ID, actorID)
+}
+
+func (s *Service) RenameHashtag(ctx context.Context, tag, newTag string, actorID int64) (*Hashtag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if !IsValidTag(newTag) {
+		return nil, ErrInvalidTag
+	}
+
+	hashtag, err := s.GetHashtagByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	newTag = NormalizeTag(newTag)
+	existing, err := s.repo.FindByTag(ctx, newTag)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTagExists
+	}
+
+	if err := s.repo.Rename(ctx, hashtag.ID, newTag, actorID); err != nil {
+		return nil, fmt.Errorf("failed to rename hashtag: %w", err)
+	}
+
+	return s.repo.FindByID(ctx, hashtag.ID)
+}
+
+func (s *Service) CreateAlias(ctx context.Context, alias, tag string, actorID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !IsValidTag(alias) {
+		return ErrInvalidTag
+	}
+
+	target, err := s.GetHashtagByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	alias = NormalizeTag(alias)
+	if alias == target.Tag {
+		return ErrSameTag
+	}
+
+	existing, err := s.repo.FindByTag(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: merge #%s into #%s instead", ErrTagExists, alias, target.Tag)
+	}
+
+	return s.repo.CreateAlias(ctx, alias, target.ID, actorID)
+}
+
+func (s *Service) DeleteAlias(ctx context.Context, alias string, actorID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.DeleteAlias(ctx, NormalizeTag(alias), actorID)
+}
+
+func (s *Service) GetAliases(ctx context.Context) ([]Alias, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	aliases, err := s.repo.FindAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if aliases == nil {
+		aliases = []Alias{}
+	}
+	return aliases, nil
+}
+
+func (s *Service) BlockHashtag(ctx context.Context, tag string, mode BlockMode, reason string, actorID int64) (*Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !IsValidTag(tag) {
+		return nil, ErrInvalidTag
+	}
+
+	block := &Block{
+		Tag:       NormalizeTag(tag),
+		Mode:      mode,
+		Reason:    reason,
+		CreatedBy: actorID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.CreateBlock(ctx, block); err != nil {
+		return nil, fmt.Errorf("failed to block hashtag: %w", err)
+	}
+
+	return block, nil
+}
+
+func (s *Service) UnblockHashtag(ctx context.Context, tag string, actorID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.DeleteBlock(ctx, NormalizeTag(tag), actorID)
+}
+
+func (s *Service) GetBlocks(ctx context.Context) ([]Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	blocks, err := s.repo.FindBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if blocks == nil {
+		blocks = []Block{}
+	}
+	return blocks, nil
+}
+
+func (s *Service) GetFlaggedPosts(ctx context.Context, params pagination.Params) (pagination.Page[FlaggedPost], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	flagged, err := s.repo.FindFlaggedPosts(ctx, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[FlaggedPost]{}, err
+	}
+
+	return pagination.NewPage(flagged, params.Limit, func(f FlaggedPost) *pagination.Cursor {
+		return pagination.NewTimeCursor(f.CreatedAt, f.ID)
+	}), nil
+}
+
+func (s *Service) GetAuditLog(ctx context.Context, params pagination.Params) (pagination.Page[AuditEntry], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entries, err := s.repo.FindAuditLog(ctx, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[AuditEntry]{}, err
+	}
+
+	return pagination.NewPage(entries, params.Limit, func(e AuditEntry) *pagination.Cursor {
+		return pagination.NewTimeCursor(e.CreatedAt, e.ID)
+	}), nil
+}
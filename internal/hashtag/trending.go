@@ -0,0 +1,172 @@
+package hashtag
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+type Window string
+
+const (
+	WindowHour Window = "1h"
+	WindowDay  Window = "24h"
+	WindowWeek Window = "7d"
+)
+
+const MinTrendUse = 2
+
+var ErrInvalidWindow = errors.New("invalid window, expected 1h, 24h or 7d")
+
+type UsageBucket struct {
+	HashtagID int64     `json:"-"`
+	Bucket    time.Time `json:"bucket"`
+	Count     int       `json:"count"`
+}
+
+type TrendingHashtag struct {
+	Hashtag
+	WindowCount int     `json:"window_count"`
+	Baseline    float64 `json:"baseline"`
+	TrendScore  float64 `json:"trend_score"`
+}
+
+type UsageStats struct {
+	Tag    string        `json:"tag"`
+	Window Window        `json:"window"`
+	Total  int           `json:"total"`
+	Series []UsageBucket `json:"series"`
+}
+
+func ParseWindow(value string) (Window, error) {
+	switch Window(value) {
+	case "":
+		return WindowDay, nil
+	case WindowHour, WindowDay, WindowWeek:
+		return Window(value), nil
+	default:
+		return "", ErrInvalidWindow
+	}
+}
+
+func (w Window) Duration() time.Duration {
+	switch w {
+	case WindowHour:
+		return time.Hour
+	case WindowWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+func (w Window) BaselinePeriods() int {
+	switch w {
+	case WindowHour:
+		return 168
+	case WindowWeek:
+		return 4
+	default:
+		return 7
+	}
+}
+
+func (w Window) BaselineStart(now time.Time) time.Time {
+	return w.Start(now).Add(-time.Duration(w.BaselinePeriods()) * w.Duration())
+}
+
+func (w Window) Start(now time.Time) time.Time {
+	return now.UTC().Truncate(time.Hour).Add(time.Hour).Add(-w.Duration())
+}
+
+func TrendScore(current int, baseline []int) (float64, float64) {
+	if len(baseline) == 0 {
+		return float64(current), 0
+	}
+
+	var sum float64
+	for _, count := range baseline {
+		sum += float64(count)
+	}
+	mean := sum / float64(len(baseline))
+
+	var variance float64
+	for _, count := range baseline {
+		variance += (float64(count) - mean) * (float64(count) - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(baseline)))
+	if stddev < 1 {
+		stddev = 1
+	}
+
+	return (float64(current) - mean) / stddev, mean
+}
+
+func RankTrending(buckets []UsageBucket, window Window, now time.Time) []TrendingHashtag {
+	start := window.Start(now)
+	periods := window.BaselinePeriods()
+
+	current := make(map[int64]int)
+	baseline := make(map[int64][]int)
+	for _, b := range buckets {
+		if !b.Bucket.Before(start) {
+			current[b.HashtagID] += b.Count
+			continue
+		}
+
+		period := int((start.Sub(b.Bucket) - time.Hour) / window.Duration())
+		if period >= periods {
+			continue
+		}
+		if baseline[b.HashtagID] == nil {
+			baseline[b.HashtagID] = make([]int, periods)
+		}
+		baseline[b.HashtagID][period] += b.Count
+	}
+
+	ranked := make([]TrendingHashtag, 0, len(current))
+	for hashtagID, count := range current {
+		if count < MinTrendUse {
+			continue
+		}
+		history := baseline[hashtagID]
+		if history == nil {
+			history = make([]int, periods)
+		}
+		score, mean := TrendScore(count, history)
+		ranked = append(ranked, TrendingHashtag{
+			Hashtag:     Hashtag{ID: hashtagID},
+			WindowCount: count,
+			Baseline:    mean,
+			TrendScore:  score,
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].TrendScore != ranked[j].TrendScore {
+			return ranked[i].TrendScore > ranked[j].TrendScore
+		}
+		if ranked[i].WindowCount != ranked[j].WindowCount {
+			return ranked[i].WindowCount > ranked[j].WindowCount
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	return ranked
+}
+
+func FillSeries(buckets []UsageBucket, window Window, now time.Time) []UsageBucket {
+	counts := make(map[time.Time]int)
+	for _, b := range buckets {
+		counts[b.Bucket.UTC()] += b.Count
+	}
+
+	series := make([]UsageBucket, 0, int(window.Duration()/time.Hour))
+	end := now.UTC().Truncate(time.Hour)
+	for t := window.Start(now); !t.After(end); t = t.Add(time.Hour) {
+		series = append(series, UsageBucket{Bucket: t, Count: counts[t]})
+	}
+
+	return series
+}
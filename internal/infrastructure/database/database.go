@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"socialmediafeed/pkg/permission"
 )
 
 func RunMigrations(db *sql.DB) error {
@@ -15,7 +16,19 @@ func RunMigrations(db *sql.DB) error {
 		createNotificationsTable,
 		createFollowersTable,
 		createPostReactionsTable,
+		createHashtagRelatedTable,
+		createHashtagModerationTables,
+		createHashtagFollowsTable,
+		createUserPrivacySettingsTable,
+		createCommentReactionsTable,
+		createUserBlocksTable,
+		createUserMutesTable,
+		createMutedWordsTable,
+		createReportTables,
+		createModerationActionsTable,
+		createUserSanctionsTable,
 		createIndexes,
+		normalizeExpiryTimestamps,
 	}
 
 	for i, migration := range migrations {
@@ -24,9 +37,352 @@ func RunMigrations(db *sql.DB) error {
 		}
 	}
 
+	if err := createSearchIndexes(db); err != nil {
+		return fmt.Errorf("search index migration failed: %w", err)
+	}
+
+	if err := createHashtagUsageBuckets(db); err != nil {
+		return fmt.Errorf("hashtag usage migration failed: %w", err)
+	}
+
+	if err := createPostReactionCounts(db); err != nil {
+		return fmt.Errorf("reaction counts migration failed: %w", err)
+	}
+
+	if err := createCommentSoftDelete(db); err != nil {
+		return fmt.Errorf("comment soft delete migration failed: %w", err)
+	}
+
+	if err := createRevisionTables(db); err != nil {
+		return fmt.Errorf("revision migration failed: %w", err)
+	}
+
+	if err := addPostCommentSettings(db); err != nil {
+		return fmt.Errorf("post comment settings migration failed: %w", err)
+	}
+
+	if err := addPostVisibility(db); err != nil {
+		return fmt.Errorf("post visibility migration failed: %w", err)
+	}
+
+	if err := addPrivateAccounts(db); err != nil {
+		return fmt.Errorf("private accounts migration failed: %w", err)
+	}
+
+	if err := createRolePermissions(db); err != nil {
+		return fmt.Errorf("role permissions migration failed: %w", err)
+	}
+
+	if err := addReportCaseClaims(db); err != nil {
+		return fmt.Errorf("report case claims migration failed: %w", err)
+	}
+
 	return nil
 }
 
+func tableExists(db *sql.DB, name string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = ?`, name).Scan(&count)
+	return count > 0, err
+}
+
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	return count > 0, err
+}
+
+func createSearchIndexes(db *sql.DB) error {
+	exists, err := tableExists(db, "posts_fts")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		createSearchTables,
+		createSearchTriggers,
+		`INSERT INTO posts_fts(posts_fts) VALUES ('rebuild');`,
+		`INSERT INTO comments_fts(comments_fts) VALUES ('rebuild');`,
+		`INSERT INTO users_fts(users_fts) VALUES ('rebuild');`,
+		`INSERT INTO hashtags_fts(hashtags_fts) VALUES ('rebuild');`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createHashtagUsageBuckets(db *sql.DB) error {
+	exists, err := tableExists(db, "hashtag_usage_hourly")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		createHashtagUsageHourlyTable,
+		backfillHashtagUsageHourly,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createPostReactionCounts(db *sql.DB) error {
+	exists, err := tableExists(db, "post_reaction_counts")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		rebuildPostReactionsTable,
+		createPostReactionCountsTable,
+		backfillPostReactionCounts,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createCommentSoftDelete(db *sql.DB) error {
+	exists, err := tableExists(db, "comment_deletions")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		addCommentDeletedColumns,
+		createCommentDeletionsTable,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createRevisionTables(db *sql.DB) error {
+	exists, err := tableExists(db, "post_revisions")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		addPostEditedAtColumn,
+		createPostRevisionsTable,
+		createCommentRevisionsTable,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func addPostCommentSettings(db *sql.DB) error {
+	exists, err := columnExists(db, "posts", "comments_locked")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(addPostCommentSettingsColumns); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func addPostVisibility(db *sql.DB) error {
+	exists, err := columnExists(db, "posts", "visibility")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(addPostVisibilityColumn); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func addPrivateAccounts(db *sql.DB) error {
+	exists, err := columnExists(db, "user_privacy_settings", "private_account")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(addPrivateAccountColumn); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createRolePermissions(db *sql.DB) error {
+	exists, err := tableExists(db, "role_permissions")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(createRolePermissionsTables); err != nil {
+		return err
+	}
+
+	for role, permissions := range permission.DefaultRolePermissions {
+		for _, p := range permissions {
+			if _, err := tx.Exec(`INSERT INTO role_permissions (role, permission) VALUES (?, ?)`, role, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func addReportCaseClaims(db *sql.DB) error {
+	exists, err := columnExists(db, "report_cases", "claimed_by")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(addReportCaseClaimColumns); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const addReportCaseClaimColumns = `
+ALTER TABLE report_cases ADD COLUMN claimed_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE report_cases ADD COLUMN claimed_at DATETIME;`
+
+const createRolePermissionsTables = `
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_permission_overrides (
+    user_id INTEGER NOT NULL,
+    permission TEXT NOT NULL,
+    granted INTEGER NOT NULL,
+    granted_by INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, permission),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (granted_by) REFERENCES users(id) ON DELETE CASCADE
+);`
+
 const createUsersTable = `
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -111,21 +467,387 @@ const createPostReactionsTable = `
 CREATE TABLE IF NOT EXISTS post_reactions (
     user_id INTEGER NOT NULL,
     post_id INTEGER NOT NULL,
-    reaction_type TEXT NOT NULL CHECK(reaction_type IN ('like', 'dislike')),
+    reaction_type TEXT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (user_id, post_id),
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
     FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
 );`
 
+const createHashtagUsageHourlyTable = `
+CREATE TABLE IF NOT EXISTS hashtag_usage_hourly (
+    hashtag_id INTEGER NOT NULL,
+    bucket DATETIME NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (hashtag_id, bucket),
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_hashtag_usage_hourly_bucket ON hashtag_usage_hourly(bucket);`
+
+const backfillHashtagUsageHourly = `
+INSERT INTO hashtag_usage_hourly (hashtag_id, bucket, count)
+SELECT ph.hashtag_id, strftime('%Y-%m-%d %H:00:00', p.created_at), COUNT(*)
+FROM post_hashtags ph
+INNER JOIN posts p ON p.id = ph.post_id
+WHERE strftime('%Y-%m-%d %H:00:00', p.created_at) IS NOT NULL
+GROUP BY ph.hashtag_id, strftime('%Y-%m-%d %H:00:00', p.created_at);`
+
+const rebuildPostReactionsTable = `
+CREATE TABLE post_reactions_rebuild (
+    user_id INTEGER NOT NULL,
+    post_id INTEGER NOT NULL,
+    reaction_type TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+INSERT INTO post_reactions_rebuild (user_id, post_id, reaction_type, created_at)
+SELECT user_id, post_id, reaction_type, created_at FROM post_reactions;
+DROP TABLE post_reactions;
+ALTER TABLE post_reactions_rebuild RENAME TO post_reactions;
+CREATE INDEX IF NOT EXISTS idx_post_reactions_user ON post_reactions(user_id);
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post ON post_reactions(post_id);`
+
+const createPostReactionCountsTable = `
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+    post_id INTEGER NOT NULL,
+    reaction_type TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, reaction_type),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);`
+
+const backfillPostReactionCounts = `
+INSERT INTO post_reaction_counts (post_id, reaction_type, count)
+SELECT post_id, reaction_type, COUNT(*) FROM post_reactions GROUP BY post_id, reaction_type;`
+
+const createHashtagRelatedTable = `
+CREATE TABLE IF NOT EXISTS hashtag_related (
+    hashtag_id INTEGER NOT NULL,
+    related_id INTEGER NOT NULL,
+    pair_count INTEGER NOT NULL,
+    lift REAL NOT NULL,
+    computed_at DATETIME NOT NULL,
+    PRIMARY KEY (hashtag_id, related_id),
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);`
+
+const createHashtagModerationTables = `
+CREATE TABLE IF NOT EXISTS hashtag_aliases (
+    alias TEXT PRIMARY KEY,
+    hashtag_id INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS hashtag_blocks (
+    tag TEXT PRIMARY KEY,
+    mode TEXT NOT NULL CHECK(mode IN ('reject', 'flag')),
+    reason TEXT NOT NULL DEFAULT '',
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS hashtag_flags (
+    post_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, tag),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS hashtag_audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    actor_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    target_tag TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_hashtag_aliases_hashtag ON hashtag_aliases(hashtag_id);
+CREATE INDEX IF NOT EXISTS idx_hashtag_flags_created ON hashtag_flags(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_hashtag_audit_log_created ON hashtag_audit_log(created_at DESC);`
+
+const createHashtagFollowsTable = `
+CREATE TABLE IF NOT EXISTS hashtag_follows (
+    user_id INTEGER NOT NULL,
+    hashtag_id INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, hashtag_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_hashtag_follows_hashtag ON hashtag_follows(hashtag_id);`
+
+const createUserPrivacySettingsTable = `
+CREATE TABLE IF NOT EXISTS user_privacy_settings (
+    user_id INTEGER PRIMARY KEY,
+    show_likes INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createUserBlocksTable = `
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id INTEGER NOT NULL,
+    blocked_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createUserMutesTable = `
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id INTEGER NOT NULL,
+    muted_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (muter_id, muted_id),
+    FOREIGN KEY (muter_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (muted_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createMutedWordsTable = `
+CREATE TABLE IF NOT EXISTS muted_words (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK(kind IN ('word', 'phrase', 'regex', 'hashtag')),
+    action TEXT NOT NULL DEFAULT 'hide' CHECK(action IN ('hide', 'collapse')),
+    expires_at DATETIME,
+    created_at DATETIME NOT NULL,
+    UNIQUE(user_id, kind, pattern),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createReportTables = `
+CREATE TABLE IF NOT EXISTS report_cases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_type TEXT NOT NULL CHECK(target_type IN ('post', 'comment', 'user')),
+    target_id INTEGER NOT NULL,
+    target_owner_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK(status IN ('open', 'resolved')),
+    report_count INTEGER NOT NULL DEFAULT 0,
+    first_reported_at DATETIME NOT NULL,
+    last_reported_at DATETIME NOT NULL,
+    resolution TEXT CHECK(resolution IN ('dismiss', 'remove', 'warn', 'ban')),
+    resolved_by INTEGER,
+    resolution_note TEXT NOT NULL DEFAULT '',
+    resolved_at DATETIME,
+    FOREIGN KEY (target_owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    case_id INTEGER NOT NULL,
+    reporter_id INTEGER NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INTEGER NOT NULL,
+    reason TEXT NOT NULL CHECK(reason IN ('spam', 'harassment', 'hate_speech', 'violence', 'sexual_content', 'misinformation', 'self_harm', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    UNIQUE(case_id, reporter_id),
+    FOREIGN KEY (case_id) REFERENCES report_cases(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createModerationActionsTable = `
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    moderator_id INTEGER NOT NULL,
+    action TEXT NOT NULL CHECK(action IN ('dismiss', 'remove', 'warn', 'ban')),
+    target_type TEXT NOT NULL CHECK(target_type IN ('post', 'comment', 'user')),
+    target_id INTEGER NOT NULL,
+    case_id INTEGER,
+    note TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (case_id) REFERENCES report_cases(id) ON DELETE SET NULL
+);`
+
+const createUserSanctionsTable = `
+CREATE TABLE IF NOT EXISTS user_sanctions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    moderator_id INTEGER NOT NULL,
+    type TEXT NOT NULL CHECK(type IN ('suspension', 'ban', 'mute')),
+    reason TEXT NOT NULL,
+    prior_role TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME,
+    ended_at DATETIME,
+    revoked_by INTEGER,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (moderator_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (revoked_by) REFERENCES users(id) ON DELETE SET NULL
+);`
+
+const normalizeExpiryTimestamps = `
+UPDATE user_sanctions SET expires_at = strftime('%Y-%m-%d %H:%M:%f+00:00', expires_at)
+WHERE expires_at IS NOT NULL AND expires_at NOT LIKE '%+00:00';
+UPDATE muted_words SET expires_at = strftime('%Y-%m-%d %H:%M:%f+00:00', expires_at)
+WHERE expires_at IS NOT NULL AND expires_at NOT LIKE '%+00:00';`
+
+const addCommentDeletedColumns = `
+ALTER TABLE comments ADD COLUMN deleted_at DATETIME;
+ALTER TABLE comments ADD COLUMN deleted_by INTEGER REFERENCES users(id);`
+
+const createCommentDeletionsTable = `
+CREATE TABLE IF NOT EXISTS comment_deletions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL,
+    post_id INTEGER NOT NULL,
+    author_id INTEGER NOT NULL,
+    parent_comment_id INTEGER,
+    content TEXT NOT NULL,
+    deleted_by INTEGER NOT NULL,
+    deleted_at DATETIME NOT NULL,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (deleted_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comment_deletions_deleted ON comment_deletions(deleted_at DESC);`
+
+const addPostEditedAtColumn = `ALTER TABLE posts ADD COLUMN edited_at DATETIME;`
+
+const addPostCommentSettingsColumns = `
+ALTER TABLE posts ADD COLUMN comments_locked INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN pinned_comment_id INTEGER REFERENCES comments(id);`
+
+const addPostVisibilityColumn = `
+ALTER TABLE posts ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public' CHECK(visibility IN ('public', 'followers', 'mentioned'));
+CREATE TABLE IF NOT EXISTS post_mentions (
+    post_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_post_mentions_user ON post_mentions(user_id);`
+
+const addPrivateAccountColumn = `
+ALTER TABLE user_privacy_settings ADD COLUMN private_account INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS follow_requests (
+    requester_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (requester_id, target_id),
+    FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_follow_requests_target ON follow_requests(target_id, created_at);`
+
+const createPostRevisionsTable = `
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    image_url TEXT,
+    editor_id INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    UNIQUE (post_id, version),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createCommentRevisionsTable = `
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    editor_id INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    UNIQUE (comment_id, version),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE
+);`
+
+const createCommentReactionsTable = `
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    user_id INTEGER NOT NULL,
+    comment_id INTEGER NOT NULL,
+    reaction_type TEXT NOT NULL CHECK(reaction_type IN ('like', 'dislike')),
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, comment_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);`
+
 const createIndexes = `
 CREATE INDEX IF NOT EXISTS idx_posts_author ON posts(author_id);
 CREATE INDEX IF NOT EXISTS idx_posts_created ON posts(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_hashtags_tag ON hashtags(tag);
+CREATE INDEX IF NOT EXISTS idx_users_username_nocase ON users(username COLLATE NOCASE);
 CREATE INDEX IF NOT EXISTS idx_comments_post ON comments(post_id);
 CREATE INDEX IF NOT EXISTS idx_comments_user ON comments(user_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_comment_id, created_at, id);
 CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id);
 CREATE INDEX IF NOT EXISTS idx_notifications_user_read ON notifications(user_id, is_read);
 CREATE INDEX IF NOT EXISTS idx_post_reactions_user ON post_reactions(user_id);
 CREATE INDEX IF NOT EXISTS idx_post_reactions_post ON post_reactions(post_id);
+CREATE INDEX IF NOT EXISTS idx_comment_reactions_comment ON comment_reactions(comment_id);
+CREATE INDEX IF NOT EXISTS idx_post_hashtags_hashtag ON post_hashtags(hashtag_id);
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks(blocked_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_report_cases_open_target ON report_cases(target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_report_cases_queue ON report_cases(status, report_count DESC, first_reported_at, id);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_created ON moderation_actions(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_sanctions_user ON user_sanctions(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_sanctions_expiring ON user_sanctions(expires_at) WHERE ended_at IS NULL AND expires_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_muted_words_expires ON muted_words(expires_at) WHERE expires_at IS NOT NULL;
+`
+
+const createSearchTables = `
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(content, content='posts', content_rowid='id');
+CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(content, content='comments', content_rowid='id');
+CREATE VIRTUAL TABLE IF NOT EXISTS users_fts USING fts5(username, content='users', content_rowid='id');
+CREATE VIRTUAL TABLE IF NOT EXISTS hashtags_fts USING fts5(tag, content='hashtags', content_rowid='id');
+`
+
+const createSearchTriggers = `
+CREATE TRIGGER IF NOT EXISTS posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(rowid, content) VALUES (new.id, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS posts_fts_delete AFTER DELETE ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS posts_fts_update AFTER UPDATE OF content ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, content) VALUES ('delete', old.id, old.content);
+    INSERT INTO posts_fts(rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_insert AFTER INSERT ON comments BEGIN
+    INSERT INTO comments_fts(rowid, content) VALUES (new.id, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_delete AFTER DELETE ON comments BEGIN
+    INSERT INTO comments_fts(comments_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+    INSERT INTO comments_fts(comments_fts, rowid, content) VALUES ('delete', old.id, old.content);
+    INSERT INTO comments_fts(rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_fts_insert AFTER INSERT ON users BEGIN
+    INSERT INTO users_fts(rowid, username) VALUES (new.id, new.username);
+END;
+CREATE TRIGGER IF NOT EXISTS users_fts_delete AFTER DELETE ON users BEGIN
+    INSERT INTO users_fts(users_fts, rowid, username) VALUES ('delete', old.id, old.username);
+END;
+CREATE TRIGGER IF NOT EXISTS users_fts_update AFTER UPDATE OF username ON users BEGIN
+    INSERT INTO users_fts(users_fts, rowid, username) VALUES ('delete', old.id, old.username);
+    INSERT INTO users_fts(rowid, username) VALUES (new.id, new.username);
+END;
+
+CREATE TRIGGER IF NOT EXISTS hashtags_fts_insert AFTER INSERT ON hashtags BEGIN
+    INSERT INTO hashtags_fts(rowid, tag) VALUES (new.id, new.tag);
+END;
+CREATE TRIGGER IF NOT EXISTS hashtags_fts_delete AFTER DELETE ON hashtags BEGIN
+    INSERT INTO hashtags_fts(hashtags_fts, rowid, tag) VALUES ('delete', old.id, old.tag);
+END;
+CREATE TRIGGER IF NOT EXISTS hashtags_fts_update AFTER UPDATE OF tag ON hashtags BEGIN
+    INSERT INTO hashtags_fts(hashtags_fts, rowid, tag) VALUES ('delete', old.id, old.tag);
+    INSERT INTO hashtags_fts(rowid, tag) VALUES (new.id, new.tag);
+END;
 `
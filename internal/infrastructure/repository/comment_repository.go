@@ -3,7 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"socialmediafeed/internal/comment"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
+	"strings"
+	"time"
 )
 
 type CommentRepositoryImpl struct {
@@ -29,14 +34,15 @@ func (r *CommentRepositoryImpl) Create(ctx context.Context, c *comment.Comment)
 }
 
 func (r *CommentRepositoryImpl) FindByID(ctx context.Context, id int64) (*comment.Comment, error) {
-	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, u.username
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username,
+	                 (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id)
 	          FROM comments c
 	          JOIN users u ON c.user_id = u.id
 	          WHERE c.id = ?`
 
 	var c comment.Comment
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.Author,
+		&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.Author, &c.ReplyCount,
 	)
 
 	if err == sql.ErrNoRows {
@@ -45,14 +51,14 @@ func (r *CommentRepositoryImpl) FindByID(ctx context.Context, id int64) (*commen
 	return &c, err
 }
 
-func (r *CommentRepositoryImpl) FindByPostID(ctx context.Context, postID int64) ([]comment.Comment, error) {
-	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, u.username
+func (r *CommentRepositoryImpl) FindReplies(ctx context.Context, commentID int64) ([]comment.Comment, error) {
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username
 	          FROM comments c
 	          JOIN users u ON c.user_id = u.id
-	          WHERE c.post_id = ?
+	          WHERE c.parent_comment_id = ?
 	          ORDER BY c.created_at ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, postID)
+	rows, err := r.db.QueryContext(ctx, query, commentID)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +67,7 @@ func (r *CommentRepositoryImpl) FindByPostID(ctx context.Context, postID int64)
 	var comments []comment.Comment
 	for rows.Next() {
 		var c comment.Comment
-		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.Author)
+		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.Author)
 		if err != nil {
 			return nil, err
 		}
@@ -71,14 +77,172 @@ func (r *CommentRepositoryImpl) FindByPostID(ctx context.Context, postID int64)
 	return comments, nil
 }
 
-func (r *CommentRepositoryImpl) FindReplies(ctx context.Context, commentID int64) ([]comment.Comment, error) {
-	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, u.username
+func (r *CommentRepositoryImpl) FindPageByPostID(ctx context.Context, postID, viewerID int64, cursor *pagination.Cursor, limit int) ([]comment.Comment, error) {
+	keyset, keysetArgs, err := keysetAfter("c.created_at", "c.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notBlocked, notBlockedArgs := notBlockedWith("c.user_id", viewerID)
+
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username
 	          FROM comments c
 	          JOIN users u ON c.user_id = u.id
-	          WHERE c.parent_comment_id = ?
-	          ORDER BY c.created_at ASC`
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.post_id = ?` + visible + notBlocked + keyset + `
+	          ORDER BY c.created_at ASC, c.id ASC
+	          LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, commentID)
+	args := append(append(append([]interface{}{postID}, visibleArgs...), notBlockedArgs...), keysetArgs...)
+	return r.queryComments(ctx, query, append(args, limit)...)
+}
+
+func (r *CommentRepositoryImpl) FindRootPage(ctx context.Context, postID, viewerID int64, sort string, cursor *pagination.Cursor, limit int) ([]comment.Comment, error) {
+	keyset, keysetArgs, err := commentKeyset(sort, cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notBlocked, notBlockedArgs := notBlockedWith("c.user_id", viewerID)
+	replyCount, replyCountArgs := visibleReplyCount(viewerID)
+
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username,
+	                 ` + replyCount + `, c.score
+	          FROM ` + scoredComments(sort) + ` c
+	          JOIN users u ON c.user_id = u.id
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.post_id = ? AND c.parent_comment_id IS NULL` + visible + notBlocked + keyset + `
+	          ORDER BY ` + commentOrder(sort) + `
+	          LIMIT ?`
+
+	args := append(append(append(append(replyCountArgs, postID), visibleArgs...), notBlockedArgs...), keysetArgs...)
+	return r.queryThreadComments(ctx, query, append(args, limit)...)
+}
+
+func (r *CommentRepositoryImpl) FindReplyPage(ctx context.Context, parentID, viewerID int64, sort string, cursor *pagination.Cursor, limit int) ([]comment.Comment, error) {
+	keyset, keysetArgs, err := commentKeyset(sort, cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notBlocked, notBlockedArgs := notBlockedWith("c.user_id", viewerID)
+	replyCount, replyCountArgs := visibleReplyCount(viewerID)
+
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username,
+	                 ` + replyCount + `, c.score
+	          FROM ` + scoredComments(sort) + ` c
+	          JOIN users u ON c.user_id = u.id
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.parent_comment_id = ?` + visible + notBlocked + keyset + `
+	          ORDER BY ` + commentOrder(sort) + `
+	          LIMIT ?`
+
+	args := append(append(append(append(replyCountArgs, parentID), visibleArgs...), notBlockedArgs...), keysetArgs...)
+	return r.queryThreadComments(ctx, query, append(args, limit)...)
+}
+
+func (r *CommentRepositoryImpl) FindDescendants(ctx context.Context, parentIDs []int64, viewerID int64, sort string, depth, perParent int) ([]comment.Comment, error) {
+	if len(parentIDs) == 0 || depth <= 0 {
+		return nil, nil
+	}
+
+	placeholders, args := commentIDPlaceholders(parentIDs)
+	rootNotBlocked, notBlockedArgs := notBlockedWith("comments.user_id", viewerID)
+	childNotBlocked, _ := notBlockedWith("c.user_id", viewerID)
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	replyCount, replyCountArgs := visibleReplyCount(viewerID)
+	query := fmt.Sprintf(`WITH RECURSIVE thread(id, depth) AS (
+	              SELECT id, 1 FROM comments WHERE parent_comment_id IN (%s)`+rootNotBlocked+`
+	              UNION ALL
+	              SELECT c.id, t.depth + 1 FROM comments c JOIN thread t ON c.parent_comment_id = t.id WHERE t.depth < ?`+childNotBlocked+`
+	          ),
+	          ranked AS (
+	              SELECT c.*, ROW_NUMBER() OVER (PARTITION BY c.parent_comment_id ORDER BY `+commentOrder(sort)+`) AS position
+	              FROM thread t
+	              JOIN `+scoredComments(sort)+` c ON c.id = t.id
+	          )
+	          SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username,
+	                 `+replyCount+`, c.score
+	          FROM ranked c
+	          JOIN users u ON c.user_id = u.id
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.position <= ?`+visible+`
+	          ORDER BY c.created_at ASC, c.id ASC`, placeholders)
+
+	args = append(append(append(append(args, notBlockedArgs...), depth), notBlockedArgs...), replyCountArgs...)
+	return r.queryThreadComments(ctx, query, append(append(args, perParent), visibleArgs...)...)
+}
+
+func visibleReplyCount(viewerID int64) (string, []interface{}) {
+	notBlocked, notBlockedArgs := notBlockedWith("r.user_id", viewerID)
+	return `(SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id` + notBlocked + `)`, notBlockedArgs
+}
+
+func scoredComments(sort string) string {
+	return `(SELECT s.*, ` + commentScore(sort) + ` AS score FROM (
+	              SELECT comments.*,
+	                     (SELECT COUNT(*) FROM comment_reactions cr WHERE cr.comment_id = comments.id AND cr.reaction_type = 'like') AS likes,
+	                     (SELECT COUNT(*) FROM comment_reactions cr WHERE cr.comment_id = comments.id AND cr.reaction_type = 'dislike') AS dislikes
+	              FROM comments) s)`
+}
+
+func commentScore(sort string) string {
+	switch sort {
+	case comment.SortTop:
+		return `s.likes - s.dislikes`
+	case comment.SortControversial:
+		return `CASE WHEN s.likes + s.dislikes = 0 THEN 0
+		             WHEN s.likes * 1.0 / (s.likes + s.dislikes) NOT BETWEEN 0.3 AND 0.7 THEN 0
+		             ELSE (s.likes + s.dislikes) * (1 - ABS(s.likes * 1.0 / (s.likes + s.dislikes) - 0.5) * 2) END`
+	default:
+		return `0`
+	}
+}
+
+func commentOrder(sort string) string {
+	switch sort {
+	case comment.SortNew:
+		return `c.created_at DESC, c.id DESC`
+	case comment.SortTop, comment.SortControversial:
+		return `c.score DESC, c.id ASC`
+	default:
+		return `c.created_at ASC, c.id ASC`
+	}
+}
+
+func commentKeyset(sort string, cursor *pagination.Cursor) (string, []interface{}, error) {
+	switch sort {
+	case comment.SortNew:
+		return keysetBefore("c.created_at", "c.id", cursor)
+	case comment.SortTop, comment.SortControversial:
+		return keysetScore("c.score", "c.id", ">", sort, cursor)
+	default:
+		return keysetAfter("c.created_at", "c.id", cursor)
+	}
+}
+
+func (r *CommentRepositoryImpl) FindByUserID(ctx context.Context, userID, viewerID int64, cursor *pagination.Cursor, limit int) ([]comment.Comment, error) {
+	keyset, keysetArgs, err := keysetBefore("c.created_at", "c.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notBlocked, notBlockedArgs := notBlockedWith("c.user_id", viewerID)
+
+	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, c.deleted_at, u.username
+	          FROM comments c
+	          JOIN users u ON c.user_id = u.id
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.user_id = ? AND c.deleted_at IS NULL` + visible + notBlocked + keyset + `
+	          ORDER BY c.created_at DESC, c.id DESC
+	          LIMIT ?`
+
+	args := append(append(append([]interface{}{userID}, visibleArgs...), notBlockedArgs...), keysetArgs...)
+	return r.queryComments(ctx, query, append(args, limit)...)
+}
+
+func (r *CommentRepositoryImpl) queryComments(ctx context.Context, query string, args ...interface{}) ([]comment.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,25 +251,18 @@ func (r *CommentRepositoryImpl) FindReplies(ctx context.Context, commentID int64
 	var comments []comment.Comment
 	for rows.Next() {
 		var c comment.Comment
-		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.Author)
+		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.Author)
 		if err != nil {
 			return nil, err
 		}
 		comments = append(comments, c)
 	}
 
-	return comments, nil
+	return comments, rows.Err()
 }
 
-func (r *CommentRepositoryImpl) FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]comment.Comment, error) {
-	query := `SELECT c.id, c.post_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at, u.username
-	          FROM comments c
-	          JOIN users u ON c.user_id = u.id
-	          WHERE c.user_id = ?
-	          ORDER BY c.created_at DESC
-	          LIMIT ? OFFSET ?`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+func (r *CommentRepositoryImpl) queryThreadComments(ctx context.Context, query string, args ...interface{}) ([]comment.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,30 +271,164 @@ func (r *CommentRepositoryImpl) FindByUserID(ctx context.Context, userID int64,
 	var comments []comment.Comment
 	for rows.Next() {
 		var c comment.Comment
-		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.Author)
+		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.ParentCommentID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.Author, &c.ReplyCount, &c.Score)
 		if err != nil {
 			return nil, err
 		}
 		comments = append(comments, c)
 	}
 
-	return comments, nil
+	return comments, rows.Err()
 }
 
-func (r *CommentRepositoryImpl) Update(ctx context.Context, c *comment.Comment) error {
-	query := `UPDATE comments SET content = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, c.Content, c.UpdatedAt, c.ID)
-	return err
+func (r *CommentRepositoryImpl) Update(ctx context.Context, c *comment.Comment, prior revision.Revision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO comment_revisions (comment_id, version, content, editor_id, reason, created_at)
+	          SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ? FROM comment_revisions WHERE comment_id = ?`
+	if _, err := tx.ExecContext(ctx, query, c.ID, prior.Content, prior.EditorID, prior.Reason, prior.EditedAt, c.ID); err != nil {
+		return err
+	}
+
+	query = `UPDATE comments SET content = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, c.Content, c.UpdatedAt, c.ID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return comment.ErrCommentNotFound
+	}
+
+	return tx.Commit()
 }
 
-func (r *CommentRepositoryImpl) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM comments WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+func (r *CommentRepositoryImpl) FindRevisions(ctx context.Context, commentID int64) ([]revision.Revision, error) {
+	query := `SELECT cr.version, cr.content, cr.editor_id, COALESCE(u.username, ''), cr.reason, cr.created_at
+	          FROM comment_revisions cr
+	          LEFT JOIN users u ON u.id = cr.editor_id
+	          WHERE cr.comment_id = ?
+	          ORDER BY cr.version ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []revision.Revision
+	for rows.Next() {
+		var rev revision.Revision
+		if err := rows.Scan(&rev.Version, &rev.Content, &rev.EditorID, &rev.Editor, &rev.Reason, &rev.EditedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (r *CommentRepositoryImpl) SoftDelete(ctx context.Context, c *comment.Comment, deletedBy int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `UPDATE comments SET content = '', deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL`, now, deletedBy, c.ID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return comment.ErrCommentNotFound
+	}
+
+	query := `INSERT INTO comment_deletions (comment_id, post_id, author_id, parent_comment_id, content, deleted_by, deleted_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, c.ID, c.PostID, c.UserID, c.ParentCommentID, c.Content, deletedBy, now); err != nil {
+		return err
+	}
+
+	if err := purgeDeletedComments(ctx, tx, c.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func purgeDeletedComments(ctx context.Context, tx *sql.Tx, commentID int64) error {
+	id := commentID
+	for {
+		var parentID *int64
+		var replies int
+		err := tx.QueryRowContext(ctx, `SELECT c.parent_comment_id, (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id)
+		                                 FROM comments c WHERE c.id = ? AND c.deleted_at IS NOT NULL`, id).Scan(&parentID, &replies)
+		if err == sql.ErrNoRows || replies > 0 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comment_reactions WHERE comment_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comment_revisions WHERE comment_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, id); err != nil {
+			return err
+		}
+
+		if parentID == nil {
+			return nil
+		}
+		id = *parentID
+	}
+}
+
+func (r *CommentRepositoryImpl) FindDeletions(ctx context.Context, cursor *pagination.Cursor, limit int) ([]comment.Deletion, error) {
+	keyset, keysetArgs, err := keysetBefore("d.deleted_at", "d.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT d.id, d.comment_id, d.post_id, d.parent_comment_id, d.author_id, COALESCE(a.username, ''), d.content,
+	                 d.deleted_by, COALESCE(m.username, ''), d.deleted_at, c.id IS NULL
+	          FROM comment_deletions d
+	          LEFT JOIN users a ON a.id = d.author_id
+	          LEFT JOIN users m ON m.id = d.deleted_by
+	          LEFT JOIN comments c ON c.id = d.comment_id
+	          WHERE 1 = 1` + keyset + `
+	          ORDER BY d.deleted_at DESC, d.id DESC
+	          LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(keysetArgs, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deletions []comment.Deletion
+	for rows.Next() {
+		var d comment.Deletion
+		err := rows.Scan(&d.ID, &d.CommentID, &d.PostID, &d.ParentCommentID, &d.AuthorID, &d.Author, &d.Content,
+			&d.DeletedBy, &d.DeletedByName, &d.DeletedAt, &d.Purged)
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, d)
+	}
+
+	return deletions, rows.Err()
 }
 
 func (r *CommentRepositoryImpl) CountByPostID(ctx context.Context, postID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM comments WHERE post_id = ?`
+	query := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND deleted_at IS NULL`
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
@@ -145,9 +436,94 @@ func (r *CommentRepositoryImpl) CountByPostID(ctx context.Context, postID int64)
 }
 
 func (r *CommentRepositoryImpl) CountByUserID(ctx context.Context, userID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM comments WHERE user_id = ?`
+	query := `SELECT COUNT(*) FROM comments WHERE user_id = ? AND deleted_at IS NULL`
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
 	return count, err
 }
+
+func (r *CommentRepositoryImpl) SetReaction(ctx context.Context, userID, commentID int64, reactionType string) error {
+	query := `INSERT INTO comment_reactions (user_id, comment_id, reaction_type, created_at)
+	          VALUES (?, ?, ?, ?)
+	          ON CONFLICT(user_id, comment_id) DO UPDATE SET reaction_type = excluded.reaction_type, created_at = excluded.created_at
+	          WHERE comment_reactions.reaction_type != excluded.reaction_type`
+
+	_, err := r.db.ExecContext(ctx, query, userID, commentID, reactionType, time.Now())
+	return err
+}
+
+func (r *CommentRepositoryImpl) RemoveReaction(ctx context.Context, userID, commentID int64) error {
+	query := `DELETE FROM comment_reactions WHERE user_id = ? AND comment_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, commentID)
+	return err
+}
+
+func (r *CommentRepositoryImpl) GetReactionCounts(ctx context.Context, commentIDs []int64) (map[int64]comment.ReactionCounts, error) {
+	counts := make(map[int64]comment.ReactionCounts)
+	if len(commentIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := commentIDPlaceholders(commentIDs)
+	query := fmt.Sprintf(`SELECT comment_id,
+	                 SUM(CASE WHEN reaction_type = 'like' THEN 1 ELSE 0 END),
+	                 SUM(CASE WHEN reaction_type = 'dislike' THEN 1 ELSE 0 END)
+	          FROM comment_reactions
+	          WHERE comment_id IN (%s)
+	          GROUP BY comment_id`, placeholders)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID int64
+		var c comment.ReactionCounts
+		if err := rows.Scan(&commentID, &c.Likes, &c.Dislikes); err != nil {
+			return nil, err
+		}
+		counts[commentID] = c
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *CommentRepositoryImpl) GetUserReactions(ctx context.Context, userID int64, commentIDs []int64) (map[int64]string, error) {
+	reactions := make(map[int64]string)
+	if len(commentIDs) == 0 {
+		return reactions, nil
+	}
+
+	placeholders, args := commentIDPlaceholders(commentIDs)
+	query := fmt.Sprintf(`SELECT comment_id, reaction_type FROM comment_reactions WHERE user_id = ? AND comment_id IN (%s)`, placeholders)
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID int64
+		var reactionType string
+		if err := rows.Scan(&commentID, &reactionType); err != nil {
+			return nil, err
+		}
+		reactions[commentID] = reactionType
+	}
+
+	return reactions, rows.Err()
+}
+
+func commentIDPlaceholders(ids []int64) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ","), args
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"math"
+	"socialmediafeed/internal/post"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFeedScoreMatchesStrategy(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	asOf := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	posts := []*post.Post{
+		{ID: 1, Likes: 0, Dislikes: 0, CreatedAt: asOf},
+		{ID: 2, Likes: 10, Dislikes: 0, CreatedAt: asOf.Add(-time.Hour)},
+		{ID: 3, Likes: 5, Dislikes: 5, CreatedAt: asOf.Add(-90 * time.Minute)},
+		{ID: 4, Likes: 4, Dislikes: 6, CreatedAt: asOf.Add(-48 * time.Hour)},
+		{ID: 5, Likes: 2, Dislikes: 8, CreatedAt: asOf.Add(time.Hour)},
+		{ID: 98765, Likes: 7, Dislikes: 3, CreatedAt: asOf.Add(-10 * time.Minute)},
+	}
+
+	for _, name := range []string{"likes", "engagement", "trending", "controversial", "random"} {
+		t.Run(name, func(t *testing.T) {
+			strategy := post.StrategyByName(name)
+			if strategy.Name() != name {
+				t.Fatalf("StrategyByName(%q) = %q", name, strategy.Name())
+			}
+
+			score, scoreArgs := feedScore(name, asOf)
+			query := `SELECT ` + score + ` FROM (SELECT ? AS id, ? AS likes, ? AS dislikes, ? AS created_at) p`
+			for _, p := range posts {
+				var got float64
+				args := append(append([]interface{}{}, scoreArgs...), p.ID, p.Likes, p.Dislikes, p.CreatedAt)
+				if err := db.QueryRow(query, args...).Scan(&got); err != nil {
+					t.Fatal(err)
+				}
+				if want := strategy.Score(p, asOf); math.Abs(got-want) > 1e-6 {
+					t.Errorf("post %d: SQL score = %v, strategy score = %v", p.ID, got, want)
+				}
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/pkg/pagination"
+	"strings"
 	"time"
 )
 
@@ -74,30 +77,119 @@ func (r *HashtagRepositoryImpl) FindAll(ctx context.Context) ([]hashtag.Hashtag,
 	return hashtags, nil
 }
 
-func (r *HashtagRepositoryImpl) FindTrending(ctx context.Context, limit int) ([]hashtag.Hashtag, error) {
-	query := `SELECT id, tag, usage_count, created_at, updated_at 
-	          FROM hashtags 
-	          WHERE updated_at > datetime('now', '-24 hours')
-	          ORDER BY usage_count DESC 
+func (r *HashtagRepositoryImpl) FindUsageSince(ctx context.Context, activeSince, since time.Time) ([]hashtag.UsageBucket, error) {
+	query := `SELECT hashtag_id, bucket, count 
+	          FROM hashtag_usage_hourly 
+	          WHERE bucket >= ? AND hashtag_id IN (
+	              SELECT hashtag_id FROM hashtag_usage_hourly WHERE bucket >= ?
+	          )`
+
+	return r.queryUsage(ctx, query, usageBucket(since), usageBucket(activeSince))
+}
+
+func (r *HashtagRepositoryImpl) RebuildRelated(ctx context.Context, minPairs int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM hashtag_related`); err != nil {
+		return 0, err
+	}
+
+	public, publicArgs := postVisibleTo("p", 0)
+
+	query := `WITH public_tags AS (
+	              SELECT ph.post_id, ph.hashtag_id
+	              FROM post_hashtags ph
+	              INNER JOIN posts p ON p.id = ph.post_id
+	              WHERE 1 = 1` + public + `
+	          ),
+	          total AS (
+	              SELECT COUNT(DISTINCT post_id) AS posts FROM public_tags
+	          ),
+	          tag_counts AS (
+	              SELECT hashtag_id, COUNT(*) AS posts FROM public_tags GROUP BY hashtag_id
+	          ),
+	          pairs AS (
+	              SELECT a.hashtag_id AS hashtag_id, b.hashtag_id AS related_id, COUNT(*) AS pair_count
+	              FROM public_tags a
+	              INNER JOIN public_tags b ON a.post_id = b.post_id AND a.hashtag_id != b.hashtag_id
+	              GROUP BY a.hashtag_id, b.hashtag_id
+	              HAVING COUNT(*) >= ?
+	          )
+	          INSERT INTO hashtag_related (hashtag_id, related_id, pair_count, lift, computed_at)
+	          SELECT p.hashtag_id, p.related_id, p.pair_count,
+	                 CAST(p.pair_count AS REAL) * total.posts / (ca.posts * cb.posts), ?
+	          FROM pairs p
+	          INNER JOIN tag_counts ca ON ca.hashtag_id = p.hashtag_id
+	          INNER JOIN tag_counts cb ON cb.hashtag_id = p.related_id
+	          CROSS JOIN total`
+
+	args := append(publicArgs, minPairs, time.Now())
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, _ := result.RowsAffected()
+	return count, tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) FindRelated(ctx context.Context, hashtagID int64, limit int) ([]hashtag.RelatedHashtag, error) {
+	query := `SELECT h.tag, h.usage_count, hr.pair_count, hr.lift, hr.computed_at 
+	          FROM hashtag_related hr 
+	          INNER JOIN hashtags h ON h.id = hr.related_id 
+	          WHERE hr.hashtag_id = ? 
+	          ORDER BY hr.lift DESC, hr.pair_count DESC, h.tag ASC 
 	          LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, hashtagID, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var hashtags []hashtag.Hashtag
+	var related []hashtag.RelatedHashtag
 	for rows.Next() {
-		var h hashtag.Hashtag
-		err := rows.Scan(&h.ID, &h.Tag, &h.UsageCount, &h.CreatedAt, &h.UpdatedAt)
-		if err != nil {
+		var rel hashtag.RelatedHashtag
+		if err := rows.Scan(&rel.Tag, &rel.UsageCount, &rel.PairCount, &rel.Lift, &rel.ComputedAt); err != nil {
 			return nil, err
 		}
-		hashtags = append(hashtags, h)
+		rel.PMI = hashtag.PMI(rel.Lift)
+		related = append(related, rel)
 	}
 
-	return hashtags, nil
+	return related, rows.Err()
+}
+
+func (r *HashtagRepositoryImpl) FindUsageByHashtag(ctx context.Context, hashtagID int64, since time.Time) ([]hashtag.UsageBucket, error) {
+	query := `SELECT hashtag_id, bucket, count 
+	          FROM hashtag_usage_hourly 
+	          WHERE hashtag_id = ? AND bucket >= ? 
+	          ORDER BY bucket ASC`
+
+	return r.queryUsage(ctx, query, hashtagID, usageBucket(since))
+}
+
+func (r *HashtagRepositoryImpl) queryUsage(ctx context.Context, query string, args ...interface{}) ([]hashtag.UsageBucket, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []hashtag.UsageBucket
+	for rows.Next() {
+		var b hashtag.UsageBucket
+		if err := rows.Scan(&b.HashtagID, &b.Bucket, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
 }
 
 func (r *HashtagRepositoryImpl) FindPopular(ctx context.Context, limit int) ([]hashtag.Hashtag, error) {
@@ -138,9 +230,32 @@ func (r *HashtagRepositoryImpl) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *HashtagRepositoryImpl) IncrementUsage(ctx context.Context, tag string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
 	query := `UPDATE hashtags SET usage_count = usage_count + 1, updated_at = ? WHERE tag = ?`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), tag)
-	return err
+	if _, err := tx.ExecContext(ctx, query, now, tag); err != nil {
+		return err
+	}
+
+	var hashtagID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM hashtags WHERE tag = ?`, tag).Scan(&hashtagID)
+	if err == sql.ErrNoRows {
+		return tx.Commit()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := recordHashtagUsage(ctx, tx, hashtagID, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *HashtagRepositoryImpl) DecrementUsage(ctx context.Context, tag string) error {
@@ -175,6 +290,33 @@ func (r *HashtagRepositoryImpl) Search(ctx context.Context, query string, limit
 	return hashtags, nil
 }
 
+func (r *HashtagRepositoryImpl) Autocomplete(ctx context.Context, prefix string, limit int) ([]hashtag.Hashtag, error) {
+	rangeClause, args := prefixRange("tag", prefix)
+	sqlQuery := `SELECT id, tag, usage_count, created_at, updated_at 
+	             FROM hashtags 
+	             WHERE usage_count > 0` + rangeClause + `
+	             ORDER BY updated_at DESC, usage_count DESC 
+	             LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashtags []hashtag.Hashtag
+	for rows.Next() {
+		var h hashtag.Hashtag
+		err := rows.Scan(&h.ID, &h.Tag, &h.UsageCount, &h.CreatedAt, &h.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		hashtags = append(hashtags, h)
+	}
+
+	return hashtags, nil
+}
+
 func (r *HashtagRepositoryImpl) CleanupUnused(ctx context.Context, olderThan time.Duration) error {
 	cutoff := time.Now().Add(-olderThan)
 	query := `DELETE FROM hashtags WHERE usage_count = 0 AND updated_at < ?`
@@ -200,3 +342,427 @@ func (r *HashtagRepositoryImpl) GetOrCreate(ctx context.Context, tag string) (*h
 
 	return newHashtag, nil
 }
+
+func (r *HashtagRepositoryImpl) Follow(ctx context.Context, userID, hashtagID int64) error {
+	query := `INSERT OR IGNORE INTO hashtag_follows (user_id, hashtag_id, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, userID, hashtagID, time.Now())
+	return err
+}
+
+func (r *HashtagRepositoryImpl) Unfollow(ctx context.Context, userID, hashtagID int64) error {
+	query := `DELETE FROM hashtag_follows WHERE user_id = ? AND hashtag_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, hashtagID)
+	return err
+}
+
+func (r *HashtagRepositoryImpl) IsFollowing(ctx context.Context, userID, hashtagID int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM hashtag_follows WHERE user_id = ? AND hashtag_id = ?`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, hashtagID).Scan(&count)
+	return count > 0, err
+}
+
+func (r *HashtagRepositoryImpl) FindFollowedByUser(ctx context.Context, userID int64) ([]hashtag.Hashtag, error) {
+	query := `SELECT h.id, h.tag, h.usage_count, h.created_at, h.updated_at 
+	          FROM hashtags h 
+	          INNER JOIN hashtag_follows hf ON hf.hashtag_id = h.id 
+	          WHERE hf.user_id = ? 
+	          ORDER BY h.tag ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashtags []hashtag.Hashtag
+	for rows.Next() {
+		var h hashtag.Hashtag
+		err := rows.Scan(&h.ID, &h.Tag, &h.UsageCount, &h.CreatedAt, &h.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		hashtags = append(hashtags, h)
+	}
+
+	return hashtags, nil
+}
+
+func (r *HashtagRepositoryImpl) Merge(ctx context.Context, sourceID, targetID, actorID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sourceTag, targetTag string
+	if err := tx.QueryRowContext(ctx, `SELECT tag FROM hashtags WHERE id = ?`, sourceID).Scan(&sourceTag); err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT tag FROM hashtags WHERE id = ?`, targetID).Scan(&targetTag); err != nil {
+		return err
+	}
+
+	var shared int64
+	sharedQuery := `SELECT COUNT(*) FROM post_hashtags s
+	                JOIN post_hashtags t ON t.post_id = s.post_id AND t.hashtag_id = ?
+	                WHERE s.hashtag_id = ?`
+	if err := tx.QueryRowContext(ctx, sharedQuery, targetID, sourceID).Scan(&shared); err != nil {
+		return err
+	}
+
+	dropShared := `UPDATE hashtag_usage_hourly SET count = MAX(count - (
+	                   SELECT COUNT(*) FROM post_hashtags s
+	                   JOIN post_hashtags t ON t.post_id = s.post_id AND t.hashtag_id = ?
+	                   JOIN posts p ON p.id = s.post_id
+	                   WHERE s.hashtag_id = ? AND strftime('%Y-%m-%d %H:00:00', p.created_at) = hashtag_usage_hourly.bucket
+	               ), 0)
+	               WHERE hashtag_id = ?`
+	if _, err := tx.ExecContext(ctx, dropShared, targetID, sourceID, sourceID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_hashtags (post_id, hashtag_id)
+	                                    SELECT post_id, ? FROM post_hashtags WHERE hashtag_id = ?`, targetID, sourceID)
+	if err != nil {
+		return err
+	}
+	moved, _ := result.RowsAffected()
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`DELETE FROM post_hashtags WHERE hashtag_id = ?`, []interface{}{sourceID}},
+		{`UPDATE hashtags SET usage_count = (SELECT COUNT(*) FROM post_hashtags ph JOIN posts p ON p.id = ph.post_id WHERE ph.hashtag_id = ?), updated_at = ? WHERE id = ?`, []interface{}{targetID, time.Now(), targetID}},
+		{`INSERT INTO hashtag_usage_hourly (hashtag_id, bucket, count)
+		  SELECT ?, bucket, count FROM hashtag_usage_hourly WHERE hashtag_id = ? AND count > 0
+		  ON CONFLICT(hashtag_id, bucket) DO UPDATE SET count = count + excluded.count`, []interface{}{targetID, sourceID}},
+		{`DELETE FROM hashtag_usage_hourly WHERE hashtag_id = ?`, []interface{}{sourceID}},
+		{`DELETE FROM hashtag_related WHERE hashtag_id = ? OR related_id = ?`, []interface{}{sourceID, sourceID}},
+		{`INSERT OR IGNORE INTO hashtag_follows (user_id, hashtag_id, created_at)
+		  SELECT user_id, ?, created_at FROM hashtag_follows WHERE hashtag_id = ?`, []interface{}{targetID, sourceID}},
+		{`DELETE FROM hashtag_follows WHERE hashtag_id = ?`, []interface{}{sourceID}},
+		{`UPDATE hashtag_aliases SET hashtag_id = ? WHERE hashtag_id = ?`, []interface{}{targetID, sourceID}},
+		{`INSERT INTO hashtag_aliases (alias, hashtag_id, created_at) VALUES (?, ?, ?)
+		  ON CONFLICT(alias) DO UPDATE SET hashtag_id = excluded.hashtag_id`, []interface{}{sourceTag, targetID, time.Now()}},
+		{`DELETE FROM hashtags WHERE id = ?`, []interface{}{sourceID}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return err
+		}
+	}
+
+	entry := hashtag.AuditEntry{
+		Action:    hashtag.AuditMerge,
+		ActorID:   actorID,
+		Tag:       sourceTag,
+		TargetTag: targetTag,
+		Details:   fmt.Sprintf("moved %d posts, %d already tagged", moved, shared),
+	}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) Rename(ctx context.Context, id int64, newTag string, actorID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldTag string
+	if err := tx.QueryRowContext(ctx, `SELECT tag FROM hashtags WHERE id = ?`, id).Scan(&oldTag); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE hashtags SET tag = ?, updated_at = ? WHERE id = ?`, newTag, time.Now(), id); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM hashtag_aliases WHERE alias = ?`, newTag); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO hashtag_aliases (alias, hashtag_id, created_at) VALUES (?, ?, ?)
+	          ON CONFLICT(alias) DO UPDATE SET hashtag_id = excluded.hashtag_id`
+	if _, err := tx.ExecContext(ctx, query, oldTag, id, time.Now()); err != nil {
+		return err
+	}
+
+	entry := hashtag.AuditEntry{Action: hashtag.AuditRename, ActorID: actorID, Tag: oldTag, TargetTag: newTag}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) CreateAlias(ctx context.Context, alias string, hashtagID, actorID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var targetTag string
+	if err := tx.QueryRowContext(ctx, `SELECT tag FROM hashtags WHERE id = ?`, hashtagID).Scan(&targetTag); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO hashtag_aliases (alias, hashtag_id, created_at) VALUES (?, ?, ?)
+	          ON CONFLICT(alias) DO UPDATE SET hashtag_id = excluded.hashtag_id`
+	if _, err := tx.ExecContext(ctx, query, alias, hashtagID, time.Now()); err != nil {
+		return err
+	}
+
+	entry := hashtag.AuditEntry{Action: hashtag.AuditAlias, ActorID: actorID, Tag: alias, TargetTag: targetTag}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) DeleteAlias(ctx context.Context, alias string, actorID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM hashtag_aliases WHERE alias = ?`, alias)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return hashtag.ErrAliasNotFound
+	}
+
+	entry := hashtag.AuditEntry{Action: hashtag.AuditRemoveAlias, ActorID: actorID, Tag: alias}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) FindAliasTarget(ctx context.Context, alias string) (string, error) {
+	query := `SELECT h.tag FROM hashtag_aliases a INNER JOIN hashtags h ON h.id = a.hashtag_id WHERE a.alias = ?`
+
+	var tag string
+	err := r.db.QueryRowContext(ctx, query, alias).Scan(&tag)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return tag, err
+}
+
+func (r *HashtagRepositoryImpl) FindAliases(ctx context.Context) ([]hashtag.Alias, error) {
+	query := `SELECT a.alias, h.tag, a.hashtag_id, a.created_at 
+	          FROM hashtag_aliases a 
+	          INNER JOIN hashtags h ON h.id = a.hashtag_id 
+	          ORDER BY a.alias ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []hashtag.Alias
+	for rows.Next() {
+		var a hashtag.Alias
+		if err := rows.Scan(&a.Alias, &a.Tag, &a.HashtagID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, a)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (r *HashtagRepositoryImpl) CreateBlock(ctx context.Context, b *hashtag.Block) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO hashtag_blocks (tag, mode, reason, created_by, created_at) VALUES (?, ?, ?, ?, ?)
+	          ON CONFLICT(tag) DO UPDATE SET mode = excluded.mode, reason = excluded.reason,
+	                                         created_by = excluded.created_by, created_at = excluded.created_at`
+	if _, err := tx.ExecContext(ctx, query, b.Tag, b.Mode, b.Reason, b.CreatedBy, b.CreatedAt); err != nil {
+		return err
+	}
+
+	details := string(b.Mode)
+	if b.Reason != "" {
+		details += ": " + b.Reason
+	}
+
+	entry := hashtag.AuditEntry{Action: hashtag.AuditBlock, ActorID: b.CreatedBy, Tag: b.Tag, Details: details}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) DeleteBlock(ctx context.Context, tag string, actorID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM hashtag_blocks WHERE tag = ?`, tag)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return hashtag.ErrBlockNotFound
+	}
+
+	entry := hashtag.AuditEntry{Action: hashtag.AuditUnblock, ActorID: actorID, Tag: tag}
+	if err := writeHashtagAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *HashtagRepositoryImpl) FindBlock(ctx context.Context, tag string) (*hashtag.Block, error) {
+	query := `SELECT tag, mode, reason, created_by, created_at FROM hashtag_blocks WHERE tag = ?`
+
+	var b hashtag.Block
+	err := r.db.QueryRowContext(ctx, query, tag).Scan(&b.Tag, &b.Mode, &b.Reason, &b.CreatedBy, &b.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *HashtagRepositoryImpl) FindBlocks(ctx context.Context) ([]hashtag.Block, error) {
+	query := `SELECT tag, mode, reason, created_by, created_at FROM hashtag_blocks ORDER BY tag ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []hashtag.Block
+	for rows.Next() {
+		var b hashtag.Block
+		if err := rows.Scan(&b.Tag, &b.Mode, &b.Reason, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+
+	return blocks, rows.Err()
+}
+
+func (r *HashtagRepositoryImpl) FlagPost(ctx context.Context, postID int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(tags))
+	args := make([]interface{}, 0, len(tags)*3)
+	now := time.Now()
+	for i, tag := range tags {
+		placeholders[i] = "(?, ?, ?)"
+		args = append(args, postID, tag, now)
+	}
+
+	query := `INSERT OR IGNORE INTO hashtag_flags (post_id, tag, created_at) VALUES ` + strings.Join(placeholders, ", ")
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *HashtagRepositoryImpl) FindFlaggedPosts(ctx context.Context, cursor *pagination.Cursor, limit int) ([]hashtag.FlaggedPost, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "rowid", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT rowid, post_id, tag, created_at FROM hashtag_flags WHERE 1 = 1` + keyset + `
+	          ORDER BY created_at DESC, rowid DESC LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(keysetArgs, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flagged []hashtag.FlaggedPost
+	for rows.Next() {
+		var f hashtag.FlaggedPost
+		if err := rows.Scan(&f.ID, &f.PostID, &f.Tag, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		flagged = append(flagged, f)
+	}
+
+	return flagged, rows.Err()
+}
+
+func (r *HashtagRepositoryImpl) FindAuditLog(ctx context.Context, cursor *pagination.Cursor, limit int) ([]hashtag.AuditEntry, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, action, actor_id, tag, target_tag, details, created_at 
+	          FROM hashtag_audit_log WHERE 1 = 1` + keyset + `
+	          ORDER BY created_at DESC, id DESC LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(keysetArgs, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []hashtag.AuditEntry
+	for rows.Next() {
+		var e hashtag.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Action, &e.ActorID, &e.Tag, &e.TargetTag, &e.Details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+func writeHashtagAudit(ctx context.Context, db execer, entry hashtag.AuditEntry) error {
+	query := `INSERT INTO hashtag_audit_log (action, actor_id, tag, target_tag, details, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := db.ExecContext(ctx, query, entry.Action, entry.ActorID, entry.Tag, entry.TargetTag, entry.Details, time.Now())
+	return err
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func usageBucket(t time.Time) string {
+	return t.UTC().Truncate(time.Hour).Format("2006-01-02 15:04:05")
+}
+
+func recordHashtagUsage(ctx context.Context, db execer, hashtagID int64, at time.Time) error {
+	query := `INSERT INTO hashtag_usage_hourly (hashtag_id, bucket, count) VALUES (?, ?, 1)
+	          ON CONFLICT(hashtag_id, bucket) DO UPDATE SET count = count + 1`
+	_, err := db.ExecContext(ctx, query, hashtagID, usageBucket(at))
+	return err
+}
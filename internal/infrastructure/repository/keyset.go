@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+	"socialmediafeed/pkg/pagination"
+)
+
+func keysetBefore(timeColumn, idColumn string, cursor *pagination.Cursor) (string, []interface{}, error) {
+	return keyset(timeColumn, idColumn, "<", cursor)
+}
+
+func keysetAfter(timeColumn, idColumn string, cursor *pagination.Cursor) (string, []interface{}, error) {
+	return keyset(timeColumn, idColumn, ">", cursor)
+}
+
+func keyset(timeColumn, idColumn, op string, cursor *pagination.Cursor) (string, []interface{}, error) {
+	if cursor == nil {
+		return "", nil, nil
+	}
+
+	t, err := cursor.Time()
+	if err != nil {
+		return "", nil, err
+	}
+
+	clause := fmt.Sprintf(" AND (%s %s ? OR (%s = ? AND %s %s ?))", timeColumn, op, timeColumn, idColumn, op)
+	return clause, []interface{}{t, t, cursor.ID}, nil
+}
+
+func keysetScore(scoreColumn, idColumn, idOp, sort string, cursor *pagination.Cursor) (string, []interface{}, error) {
+	if cursor == nil {
+		return "", nil, nil
+	}
+
+	score, _, err := cursor.Score(sort)
+	if err != nil {
+		return "", nil, err
+	}
+
+	clause := fmt.Sprintf(" AND (%s < ? OR (%s = ? AND %s %s ?))", scoreColumn, scoreColumn, idColumn, idOp)
+	return clause, []interface{}{score, score, cursor.ID}, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"reflect"
+	"socialmediafeed/pkg/pagination"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestKeysetScorePaging(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE scored (id INTEGER PRIMARY KEY, score REAL NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	scores := map[int64]float64{1: 5, 2: 3, 3: 5, 4: 0.5, 5: 3, 6: 3, 7: 9, 8: 0.5}
+	for id, score := range scores {
+		if _, err := db.Exec(`INSERT INTO scored (id, score) VALUES (?, ?)`, id, score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	asOf := time.Now()
+	var cursor *pagination.Cursor
+	var got []int64
+	for page := 0; page < len(scores); page++ {
+		keyset, args, err := keysetScore("score", "id", "<", "likes", cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := db.Query(`SELECT id, score FROM scored WHERE 1 = 1`+keyset+` ORDER BY score DESC, id DESC LIMIT 3`, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var n int
+		for rows.Next() {
+			var id int64
+			var score float64
+			if err := rows.Scan(&id, &score); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, id)
+			cursor = pagination.NewScoreCursor("likes", score, asOf, id)
+			n++
+		}
+		rows.Close()
+		if n < 3 {
+			break
+		}
+	}
+
+	want := []int64{7, 3, 1, 6, 5, 2, 8, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paged ids = %v, want %v", got, want)
+	}
+
+	if _, _, err := keysetScore("score", "id", "<", "trending", cursor); err != pagination.ErrInvalidCursor {
+		t.Errorf("keysetScore() with a cursor from another sort error = %v, want %v", err, pagination.ErrInvalidCursor)
+	}
+	if _, _, err := keysetScore("score", "id", "<", "likes", pagination.NewTimeCursor(asOf, 1)); err != pagination.ErrInvalidCursor {
+		t.Errorf("keysetScore() with a time cursor error = %v, want %v", err, pagination.ErrInvalidCursor)
+	}
+}
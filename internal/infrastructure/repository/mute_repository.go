@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"socialmediafeed/internal/mute"
+	"time"
+)
+
+type MuteRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewMuteRepository(db *sql.DB) mute.Repository {
+	return &MuteRepositoryImpl{db: db}
+}
+
+func (r *MuteRepositoryImpl) Save(ctx context.Context, rule *mute.Rule) error {
+	query := `INSERT INTO muted_words (user_id, pattern, kind, action, expires_at, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(user_id, kind, pattern) DO UPDATE SET
+	              action = excluded.action,
+	              expires_at = excluded.expires_at`
+
+	_, err := r.db.ExecContext(ctx, query, rule.UserID, rule.Pattern, rule.Kind, rule.Action, utcTime(rule.ExpiresAt), rule.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx,
+		`SELECT id, created_at FROM muted_words WHERE user_id = ? AND kind = ? AND pattern = ?`,
+		rule.UserID, rule.Kind, rule.Pattern,
+	).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (r *MuteRepositoryImpl) FindActiveByUser(ctx context.Context, userID int64, now time.Time) ([]mute.Rule, error) {
+	query := `SELECT id, user_id, pattern, kind, action, expires_at, created_at
+	          FROM muted_words
+	          WHERE user_id = ? AND (expires_at IS NULL OR expires_at > ?)
+	          ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]mute.Rule, 0)
+	for rows.Next() {
+		var rule mute.Rule
+		if err := rows.Scan(&rule.ID, &rule.UserID, &rule.Pattern, &rule.Kind, &rule.Action, &rule.ExpiresAt, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *MuteRepositoryImpl) CountActiveByUser(ctx context.Context, userID int64, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM muted_words WHERE user_id = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		userID, now.UTC(),
+	).Scan(&count)
+	return count, err
+}
+
+func (r *MuteRepositoryImpl) Delete(ctx context.Context, id, userID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM muted_words WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *MuteRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM muted_words WHERE expires_at IS NOT NULL AND expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"socialmediafeed/internal/notification"
+	"socialmediafeed/pkg/pagination"
 	"time"
 )
 
@@ -44,11 +45,17 @@ func (r *NotificationRepositoryImpl) FindByID(ctx context.Context, id int64) (*n
 	return &n, err
 }
 
-func (r *NotificationRepositoryImpl) FindByUser(ctx context.Context, userID int64, limit, offset int) ([]notification.Notification, error) {
+func (r *NotificationRepositoryImpl) FindByUser(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]notification.Notification, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `SELECT id, user_id, type, title, message, is_read, related_entity_id, related_entity_type, created_at
-	          FROM notifications WHERE user_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	          FROM notifications WHERE user_id = ?` + keyset + ` ORDER BY created_at DESC, id DESC LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	args := append([]interface{}{userID}, keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
 	if err != nil {
 		return nil, err
 	}
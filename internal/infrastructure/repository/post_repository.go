@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"socialmediafeed/internal/hashtag"
 	"socialmediafeed/internal/post"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
 	"strings"
+	"time"
 )
 
 type PostRepositoryImpl struct {
@@ -24,10 +27,10 @@ func (r *PostRepositoryImpl) Create(ctx context.Context, p *post.Post) error {
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO posts (author_id, content, image_url, likes, dislikes, created_at, updated_at)
-	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO posts (author_id, content, image_url, likes, dislikes, created_at, updated_at, visibility)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := tx.ExecContext(ctx, query, p.AuthorID, p.Content, p.MediaURL, p.Likes, p.Dislikes, p.CreatedAt, p.UpdatedAt)
+	result, err := tx.ExecContext(ctx, query, p.AuthorID, p.Content, p.MediaURL, p.Likes, p.Dislikes, p.CreatedAt, p.UpdatedAt, p.Visibility)
 	if err != nil {
 		return err
 	}
@@ -35,6 +38,10 @@ func (r *PostRepositoryImpl) Create(ctx context.Context, p *post.Post) error {
 	id, _ := result.LastInsertId()
 	p.ID = id
 
+	if err := replacePostMentions(ctx, tx, p.ID, p.Mentions); err != nil {
+		return err
+	}
+
 	if len(p.Hashtags) > 0 {
 		for _, tag := range p.Hashtags {
 			var hashtagID int64
@@ -59,6 +66,10 @@ func (r *PostRepositoryImpl) Create(ctx context.Context, p *post.Post) error {
 			if err != nil {
 				return err
 			}
+
+			if err := recordHashtagUsage(ctx, tx, hashtagID, p.CreatedAt); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -66,12 +77,12 @@ func (r *PostRepositoryImpl) Create(ctx context.Context, p *post.Post) error {
 }
 
 func (r *PostRepositoryImpl) FindByID(ctx context.Context, id int64) (*post.Post, error) {
-	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at 
+	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at, edited_at, comments_locked, pinned_comment_id, visibility 
 	          FROM posts WHERE id = ?`
 
 	var p post.Post
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility,
 	)
 
 	if err == sql.ErrNoRows {
@@ -90,6 +101,19 @@ func (r *PostRepositoryImpl) FindByID(ctx context.Context, id int64) (*post.Post
 	return &p, nil
 }
 
+func (r *PostRepositoryImpl) FindVisibleByID(ctx context.Context, id, viewerID int64) (*post.Post, error) {
+	visible, visibleArgs := postVisibleTo("posts", viewerID)
+
+	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at, edited_at, comments_locked, pinned_comment_id, visibility 
+	          FROM posts WHERE id = ?` + visible
+
+	posts, err := r.queryPosts(ctx, query, append([]interface{}{id}, visibleArgs...)...)
+	if err != nil || len(posts) == 0 {
+		return nil, err
+	}
+	return posts[0], nil
+}
+
 func (r *PostRepositoryImpl) getPostHashtags(ctx context.Context, postID int64) ([]string, error) {
 	query := `SELECT h.tag FROM hashtags h
 	          INNER JOIN post_hashtags ph ON h.id = ph.hashtag_id
@@ -113,11 +137,14 @@ func (r *PostRepositoryImpl) getPostHashtags(ctx context.Context, postID int64)
 	return hashtags, nil
 }
 
-func (r *PostRepositoryImpl) FindAll(ctx context.Context) ([]*post.Post, error) {
-	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at 
-	          FROM posts ORDER BY created_at DESC`
+func (r *PostRepositoryImpl) FindAll(ctx context.Context, viewerID int64) ([]*post.Post, error) {
+	visible, visibleArgs := postVisibleTo("posts", viewerID)
+	notMuted, notMutedArgs := notMutedBy("posts.author_id", viewerID)
+
+	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at, edited_at, comments_locked, pinned_comment_id, visibility 
+	          FROM posts WHERE 1 = 1` + visible + notMuted + ` ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, append(visibleArgs, notMutedArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +153,7 @@ func (r *PostRepositoryImpl) FindAll(ctx context.Context) ([]*post.Post, error)
 	var posts []*post.Post
 	for rows.Next() {
 		p := &post.Post{}
-		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt)
+		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility)
 		if err != nil {
 			return nil, err
 		}
@@ -140,93 +167,248 @@ func (r *PostRepositoryImpl) FindAll(ctx context.Context) ([]*post.Post, error)
 	return posts, nil
 }
 
-func (r *PostRepositoryImpl) Update(ctx context.Context, p *post.Post) error {
+func (r *PostRepositoryImpl) Update(ctx context.Context, p *post.Post, prior revision.Revision) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	query := `UPDATE posts SET content = ?, image_url = ?, updated_at = ? WHERE id = ?`
-	_, err = tx.ExecContext(ctx, query, p.Content, p.MediaURL, p.UpdatedAt, p.ID)
+	query := `INSERT INTO post_revisions (post_id, version, content, image_url, editor_id, reason, created_at)
+	          SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ?, ? FROM post_revisions WHERE post_id = ?`
+	_, err = tx.ExecContext(ctx, query, p.ID, prior.Content, prior.MediaURL, prior.EditorID, prior.Reason, prior.EditedAt, p.ID)
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE posts SET content = ?, image_url = ?, updated_at = ?, edited_at = ? WHERE id = ?`
+	_, err = tx.ExecContext(ctx, query, p.Content, p.MediaURL, p.UpdatedAt, p.EditedAt, p.ID)
 	if err != nil {
 		return err
 	}
 
+	if err := replacePostMentions(ctx, tx, p.ID, p.Mentions); err != nil {
+		return err
+	}
+
+	previous := make(map[int64]bool)
+	rows, err := tx.QueryContext(ctx, `SELECT hashtag_id FROM post_hashtags WHERE post_id = ?`, p.ID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var hashtagID int64
+		if err := rows.Scan(&hashtagID); err != nil {
+			rows.Close()
+			return err
+		}
+		previous[hashtagID] = true
+	}
+	rows.Close()
+
 	_, err = tx.ExecContext(ctx, `DELETE FROM post_hashtags WHERE post_id = ?`, p.ID)
 	if err != nil {
 		return err
 	}
 
-	if len(p.Hashtags) > 0 {
-		for _, tag := range p.Hashtags {
-			var hashtagID int64
-			err := tx.QueryRowContext(ctx, `SELECT id FROM hashtags WHERE tag = ?`, tag).Scan(&hashtagID)
+	for _, tag := range p.Hashtags {
+		var hashtagID int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM hashtags WHERE tag = ?`, tag).Scan(&hashtagID)
 
-			if err == sql.ErrNoRows {
-				result, err := tx.ExecContext(ctx, `INSERT INTO hashtags (tag, usage_count, created_at, updated_at) VALUES (?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`, tag)
-				if err != nil {
-					return err
-				}
-				hashtagID, _ = result.LastInsertId()
-			} else if err != nil {
+		if err == sql.ErrNoRows {
+			result, err := tx.ExecContext(ctx, `INSERT INTO hashtags (tag, usage_count, created_at, updated_at) VALUES (?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`, tag)
+			if err != nil {
 				return err
 			}
-
-			_, err = tx.ExecContext(ctx, `INSERT INTO post_hashtags (post_id, hashtag_id) VALUES (?, ?)`, p.ID, hashtagID)
+			hashtagID, _ = result.LastInsertId()
+			if err := recordHashtagUsage(ctx, tx, hashtagID, p.UpdatedAt); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		} else if previous[hashtagID] {
+			delete(previous, hashtagID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE hashtags SET usage_count = usage_count + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, hashtagID)
 			if err != nil {
 				return err
 			}
+			if err := recordHashtagUsage(ctx, tx, hashtagID, p.UpdatedAt); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO post_hashtags (post_id, hashtag_id) VALUES (?, ?)`, p.ID, hashtagID)
+		if err != nil {
+			return err
 		}
 	}
 
-	return tx.Commit()
-}
+	for hashtagID := range previous {
+		_, err = tx.ExecContext(ctx, `UPDATE hashtags SET usage_count = MAX(0, usage_count - 1) WHERE id = ?`, hashtagID)
+		if err != nil {
+			return err
+		}
+	}
 
-func (r *PostRepositoryImpl) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM posts WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+	return tx.Commit()
 }
 
-func (r *PostRepositoryImpl) FindByAuthor(ctx context.Context, authorID int64) ([]*post.Post, error) {
-	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at 
-	          FROM posts WHERE author_id = ? ORDER BY created_at DESC`
+func (r *PostRepositoryImpl) FindRevisions(ctx context.Context, postID int64) ([]revision.Revision, error) {
+	query := `SELECT pr.version, pr.content, COALESCE(pr.image_url, ''), pr.editor_id, COALESCE(u.username, ''), pr.reason, pr.created_at
+	          FROM post_revisions pr
+	          LEFT JOIN users u ON u.id = pr.editor_id
+	          WHERE pr.post_id = ?
+	          ORDER BY pr.version ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, authorID)
+	rows, err := r.db.QueryContext(ctx, query, postID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var posts []*post.Post
+	var revisions []revision.Revision
 	for rows.Next() {
-		p := &post.Post{}
-		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt)
-		if err != nil {
+		var rev revision.Revision
+		if err := rows.Scan(&rev.Version, &rev.Content, &rev.MediaURL, &rev.EditorID, &rev.Editor, &rev.Reason, &rev.EditedAt); err != nil {
 			return nil, err
 		}
+		revisions = append(revisions, rev)
+	}
 
-		hashtags, _ := r.getPostHashtags(ctx, p.ID)
-		p.Hashtags = hashtags
+	return revisions, rows.Err()
+}
 
-		posts = append(posts, p)
+func (r *PostRepositoryImpl) SetCommentsLocked(ctx context.Context, postID int64, locked bool) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE posts SET comments_locked = ? WHERE id = ?`, locked, postID)
+	return err
+}
+
+func (r *PostRepositoryImpl) SetVisibility(ctx context.Context, postID int64, visibility post.Visibility) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE posts SET visibility = ? WHERE id = ?`, visibility, postID)
+	return err
+}
+
+func (r *PostRepositoryImpl) SetPinnedComment(ctx context.Context, postID int64, commentID *int64) error {
+	if commentID == nil {
+		_, err := r.db.ExecContext(ctx, `UPDATE posts SET pinned_comment_id = NULL WHERE id = ?`, postID)
+		return err
 	}
 
-	return posts, nil
+	query := `UPDATE posts SET pinned_comment_id = ?
+	          WHERE id = ? AND EXISTS (
+	              SELECT 1 FROM comments
+	              WHERE id = ? AND post_id = posts.id AND parent_comment_id IS NULL AND deleted_at IS NULL
+	          )`
+
+	result, err := r.db.ExecContext(ctx, query, *commentID, postID, *commentID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return post.ErrCommentNotOnPost
+	}
+	return nil
+}
+
+func (r *PostRepositoryImpl) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE hashtags SET usage_count = MAX(usage_count - 1, 0), updated_at = CURRENT_TIMESTAMP
+		 WHERE id IN (SELECT hashtag_id FROM post_hashtags WHERE post_id = ?)`,
+		`DELETE FROM post_hashtags WHERE post_id = ?`,
+		`DELETE FROM post_reactions WHERE post_id = ?`,
+		`DELETE FROM post_reaction_counts WHERE post_id = ?`,
+		`DELETE FROM post_mentions WHERE post_id = ?`,
+		`DELETE FROM posts WHERE id = ?`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostRepositoryImpl) FindByAuthor(ctx context.Context, authorID, viewerID int64, cursor *pagination.Cursor, limit int) ([]*post.Post, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("posts", viewerID)
+
+	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at, edited_at, comments_locked, pinned_comment_id, visibility 
+	          FROM posts WHERE author_id = ?` + visible + keyset + `
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT ?`
+
+	args := append(append([]interface{}{authorID}, visibleArgs...), keysetArgs...)
+	return r.queryPosts(ctx, query, append(args, limit)...)
 }
 
-func (r *PostRepositoryImpl) FindByHashtag(ctx context.Context, h *hashtag.Hashtag) ([]*post.Post, error) {
+func (r *PostRepositoryImpl) FindByHashtag(ctx context.Context, h *hashtag.Hashtag, viewerID int64, cursor *pagination.Cursor, limit int) ([]*post.Post, error) {
 	normalized := strings.ToLower(strings.TrimPrefix(h.Tag, "#"))
 
-	query := `SELECT p.id, p.author_id, p.content, p.image_url, p.likes, p.dislikes, p.created_at, p.updated_at 
+	keyset, keysetArgs, err := keysetBefore("p.created_at", "p.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notMuted, notMutedArgs := notMutedBy("p.author_id", viewerID)
+
+	query := `SELECT p.id, p.author_id, p.content, p.image_url, p.likes, p.dislikes, p.created_at, p.updated_at, p.edited_at, p.comments_locked, p.pinned_comment_id, p.visibility 
 	          FROM posts p
 	          INNER JOIN post_hashtags ph ON p.id = ph.post_id
 	          INNER JOIN hashtags ht ON ph.hashtag_id = ht.id
-	          WHERE ht.tag = ?
-	          ORDER BY p.created_at DESC`
+	          WHERE ht.tag = ?` + visible + notMuted + keyset + `
+	          ORDER BY p.created_at DESC, p.id DESC
+	          LIMIT ?`
+
+	args := append(append(append([]interface{}{normalized}, visibleArgs...), notMutedArgs...), keysetArgs...)
+	return r.queryPosts(ctx, query, append(args, limit)...)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, normalized)
+func (r *PostRepositoryImpl) FindWithPagination(ctx context.Context, viewerID int64, cursor *pagination.Cursor, limit int) ([]*post.Post, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("posts", viewerID)
+	notMuted, notMutedArgs := notMutedBy("posts.author_id", viewerID)
+
+	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at, edited_at, comments_locked, pinned_comment_id, visibility 
+	          FROM posts WHERE 1 = 1` + visible + notMuted + keyset + `
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT ?`
+
+	args := append(append(visibleArgs, notMutedArgs...), keysetArgs...)
+	return r.queryPosts(ctx, query, append(args, limit)...)
+}
+
+func (r *PostRepositoryImpl) FindFeed(ctx context.Context, viewerID int64, sort string, asOf time.Time, cursor *pagination.Cursor, limit int) ([]*post.Post, error) {
+	keyset, keysetArgs, err := keysetScore("p.score", "p.id", "<", sort, cursor)
+	if err != nil {
+		return nil, err
+	}
+	score, scoreArgs := feedScore(sort, asOf)
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+	notMuted, notMutedArgs := notMutedBy("p.author_id", viewerID)
+
+	query := `SELECT p.id, p.author_id, p.content, p.image_url, p.likes, p.dislikes, p.created_at, p.updated_at, p.edited_at, p.comments_locked, p.pinned_comment_id, p.visibility, p.score
+	          FROM (SELECT p.*, ` + score + ` AS score FROM posts p WHERE 1 = 1` + visible + notMuted + `) p
+	          WHERE 1 = 1` + keyset + `
+	          ORDER BY p.score DESC, p.id DESC
+	          LIMIT ?`
+
+	args := append(append(append(scoreArgs, visibleArgs...), notMutedArgs...), keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
 	if err != nil {
 		return nil, err
 	}
@@ -235,49 +417,112 @@ func (r *PostRepositoryImpl) FindByHashtag(ctx context.Context, h *hashtag.Hasht
 	var posts []*post.Post
 	for rows.Next() {
 		p := &post.Post{}
-		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt)
+		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility, &p.Score)
 		if err != nil {
 			return nil, err
 		}
+		posts = append(posts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	for _, p := range posts {
 		hashtags, _ := r.getPostHashtags(ctx, p.ID)
 		p.Hashtags = hashtags
-
-		posts = append(posts, p)
 	}
 
 	return posts, nil
 }
 
-func (r *PostRepositoryImpl) IncrementLikes(ctx context.Context, postID int64) error {
-	query := `UPDATE posts SET likes = likes + 1 WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, postID)
-	return err
+func feedScore(sort string, asOf time.Time) (string, []interface{}) {
+	switch sort {
+	case "likes":
+		return `p.likes`, nil
+	case "engagement":
+		return `p.likes + p.dislikes`, nil
+	case "trending":
+		return `(p.likes + p.dislikes) * 1.0 / (MAX((julianday(?) - julianday(p.created_at)) * 24, 0) + 2)`, []interface{}{asOf}
+	case "controversial":
+		return `CASE WHEN p.likes + p.dislikes = 0 THEN 0
+		             WHEN p.likes * 1.0 / (p.likes + p.dislikes) NOT BETWEEN 0.3 AND 0.7 THEN 0
+		             ELSE (p.likes + p.dislikes) * (1 - ABS(p.likes * 1.0 / (p.likes + p.dislikes) - 0.5) * 2) END`, nil
+	case "random":
+		return `(p.id * 2654435761) % 4294967296`, nil
+	default:
+		return `0`, nil
+	}
 }
 
-func (r *PostRepositoryImpl) DecrementLikes(ctx context.Context, postID int64) error {
-	query := `UPDATE posts SET likes = MAX(0, likes - 1) WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, postID)
-	return err
-}
+func (r *PostRepositoryImpl) FindTimeline(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]post.TimelineItem, error) {
+	keyset, keysetArgs, err := keysetBefore("p.created_at", "p.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", userID)
+	notMuted, notMutedArgs := notMutedBy("p.author_id", userID)
+
+	query := `SELECT p.id, p.author_id, p.content, p.image_url, p.likes, p.dislikes, p.created_at, p.updated_at, p.edited_at, p.comments_locked, p.pinned_comment_id, p.visibility,
+	                 CASE
+	                     WHEN p.author_id = ? THEN ?
+	                     WHEN f.following_id IS NOT NULL THEN ?
+	                     ELSE ?
+	                 END,
+	                 CASE
+	                     WHEN p.author_id = ? THEN ''
+	                     WHEN f.following_id IS NOT NULL THEN u.username
+	                     ELSE (SELECT MIN(h.tag) FROM post_hashtags ph
+	                           INNER JOIN hashtag_follows hf ON hf.hashtag_id = ph.hashtag_id AND hf.user_id = ?
+	                           INNER JOIN hashtags h ON h.id = ph.hashtag_id
+	                           WHERE ph.post_id = p.id)
+	                 END
+	          FROM posts p
+	          INNER JOIN users u ON u.id = p.author_id
+	          LEFT JOIN followers f ON f.follower_id = ? AND f.following_id = p.author_id
+	          WHERE (p.author_id = ? OR f.following_id IS NOT NULL OR EXISTS (
+	              SELECT 1 FROM post_hashtags ph
+	              INNER JOIN hashtag_follows hf ON hf.hashtag_id = ph.hashtag_id
+	              WHERE ph.post_id = p.id AND hf.user_id = ?
+	          ))` + visible + notMuted + keyset + `
+	          ORDER BY p.created_at DESC, p.id DESC
+	          LIMIT ?`
+
+	args := []interface{}{
+		userID, post.ReasonOwnPost, post.ReasonFollowedUser, post.ReasonFollowedHashtag,
+		userID, userID, userID, userID, userID,
+	}
+	args = append(append(append(append(args, visibleArgs...), notMutedArgs...), keysetArgs...), limit)
 
-func (r *PostRepositoryImpl) IncrementDislikes(ctx context.Context, postID int64) error {
-	query := `UPDATE posts SET dislikes = dislikes + 1 WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, postID)
-	return err
-}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-func (r *PostRepositoryImpl) DecrementDislikes(ctx context.Context, postID int64) error {
-	query := `UPDATE posts SET dislikes = MAX(0, dislikes - 1) WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, postID)
-	return err
-}
+	var items []post.TimelineItem
+	for rows.Next() {
+		p := &post.Post{}
+		var reasonType, subject string
+		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility, &reasonType, &subject)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, post.NewTimelineItem(p, reasonType, subject))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-func (r *PostRepositoryImpl) FindWithPagination(ctx context.Context, limit, offset int) ([]*post.Post, error) {
-	query := `SELECT id, author_id, content, image_url, likes, dislikes, created_at, updated_at 
-	          FROM posts ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	for _, item := range items {
+		hashtags, _ := r.getPostHashtags(ctx, item.ID)
+		item.Hashtags = hashtags
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	return items, nil
+}
+
+func (r *PostRepositoryImpl) queryPosts(ctx context.Context, query string, args ...interface{}) ([]*post.Post, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -286,43 +531,214 @@ func (r *PostRepositoryImpl) FindWithPagination(ctx context.Context, limit, offs
 	var posts []*post.Post
 	for rows.Next() {
 		p := &post.Post{}
-		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt)
+		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility)
 		if err != nil {
 			return nil, err
 		}
+		posts = append(posts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	for _, p := range posts {
 		hashtags, _ := r.getPostHashtags(ctx, p.ID)
 		p.Hashtags = hashtags
-
-		posts = append(posts, p)
 	}
 
 	return posts, nil
 }
 
-func (r *PostRepositoryImpl) HasUserReacted(ctx context.Context, userID, postID int64) (bool, string, error) {
-	query := `SELECT reaction_type FROM post_reactions WHERE user_id = ? AND post_id = ?`
-	var reactionType string
-	err := r.db.QueryRowContext(ctx, query, userID, postID).Scan(&reactionType)
-	if err == sql.ErrNoRows {
-		return false, "", nil
+func (r *PostRepositoryImpl) SetReaction(ctx context.Context, userID, postID int64, reactionType string, kinds post.ReactionKinds) (*post.ReactionState, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPostCounters(ctx, tx, postID); err != nil {
+		return nil, err
 	}
+
+	previous, err := findReaction(ctx, tx, userID, postID)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
-	return true, reactionType, nil
+
+	if previous != reactionType {
+		query := `INSERT INTO post_reactions (user_id, post_id, reaction_type, created_at) VALUES (?, ?, ?, ?)
+		          ON CONFLICT(user_id, post_id) DO UPDATE SET reaction_type = excluded.reaction_type, created_at = excluded.created_at`
+		if _, err := tx.ExecContext(ctx, query, userID, postID, reactionType, time.Now()); err != nil {
+			return nil, err
+		}
+		if err := adjustReactionCounters(ctx, tx, postID, previous, reactionType, kinds); err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := findReactionState(ctx, tx, postID)
+	if err != nil {
+		return nil, err
+	}
+	state.Reaction = reactionType
+	state.Previous = previous
+
+	return state, tx.Commit()
 }
 
-func (r *PostRepositoryImpl) AddReaction(ctx context.Context, userID, postID int64, reactionType string) error {
-	query := `INSERT INTO post_reactions (user_id, post_id, reaction_type) VALUES (?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, userID, postID, reactionType)
-	return err
+func (r *PostRepositoryImpl) RemoveReaction(ctx context.Context, userID, postID int64, kinds post.ReactionKinds) (*post.ReactionState, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPostCounters(ctx, tx, postID); err != nil {
+		return nil, err
+	}
+
+	previous, err := findReaction(ctx, tx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != "" {
+		query := `DELETE FROM post_reactions WHERE user_id = ? AND post_id = ?`
+		if _, err := tx.ExecContext(ctx, query, userID, postID); err != nil {
+			return nil, err
+		}
+		if err := adjustReactionCounters(ctx, tx, postID, previous, "", kinds); err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := findReactionState(ctx, tx, postID)
+	if err != nil {
+		return nil, err
+	}
+	state.Previous = previous
+
+	return state, tx.Commit()
 }
 
-func (r *PostRepositoryImpl) UpdateReaction(ctx context.Context, userID, postID int64, oldType, newType string) error {
-	query := `UPDATE post_reactions SET reaction_type = ? WHERE user_id = ? AND post_id = ?`
-	_, err := r.db.ExecContext(ctx, query, newType, userID, postID)
-	return err
+func (r *PostRepositoryImpl) GetReactionCounts(ctx context.Context, postIDs []int64) (map[int64]map[string]int, error) {
+	counts := make(map[int64]map[string]int)
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]interface{}, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`SELECT post_id, reaction_type, count FROM post_reaction_counts WHERE post_id IN (%s) AND count > 0`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID int64
+		var reactionType string
+		var count int
+		if err := rows.Scan(&postID, &reactionType, &count); err != nil {
+			return nil, err
+		}
+		if counts[postID] == nil {
+			counts[postID] = make(map[string]int)
+		}
+		counts[postID][reactionType] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *PostRepositoryImpl) FindReactors(ctx context.Context, postID, viewerID int64, reactionType string, cursor *pagination.Cursor, limit int) ([]post.Reactor, error) {
+	keyset, keysetArgs, err := keysetBefore("pr.created_at", "pr.user_id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{postID}
+	typeClause := ""
+	if reactionType != "" {
+		typeClause = " AND pr.reaction_type = ?"
+		args = append(args, reactionType)
+	}
+	notBlocked, notBlockedArgs := notBlockedWith("pr.user_id", viewerID)
+
+	query := `SELECT pr.user_id, u.username, pr.reaction_type, pr.created_at
+	          FROM post_reactions pr
+	          INNER JOIN users u ON u.id = pr.user_id
+	          WHERE pr.post_id = ?` + typeClause + notBlocked + keyset + `
+	          ORDER BY pr.created_at DESC, pr.user_id DESC
+	          LIMIT ?`
+
+	args = append(append(append(args, notBlockedArgs...), keysetArgs...), limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactors []post.Reactor
+	for rows.Next() {
+		var reactor post.Reactor
+		if err := rows.Scan(&reactor.UserID, &reactor.Username, &reactor.Reaction, &reactor.ReactedAt); err != nil {
+			return nil, err
+		}
+		reactors = append(reactors, reactor)
+	}
+
+	return reactors, rows.Err()
+}
+
+func (r *PostRepositoryImpl) FindLikedPosts(ctx context.Context, userID, viewerID int64, cursor *pagination.Cursor, limit int) ([]post.LikedPost, error) {
+	keyset, keysetArgs, err := keysetBefore("pr.created_at", "pr.post_id", cursor)
+	if err != nil {
+		return nil, err
+	}
+	visible, visibleArgs := postVisibleTo("p", viewerID)
+
+	query := `SELECT p.id, p.author_id, p.content, p.image_url, p.likes, p.dislikes, p.created_at, p.updated_at, p.edited_at, p.comments_locked, p.pinned_comment_id, p.visibility, pr.created_at
+	          FROM post_reactions pr
+	          INNER JOIN posts p ON p.id = pr.post_id
+	          WHERE pr.user_id = ? AND pr.reaction_type = ?` + visible + keyset + `
+	          ORDER BY pr.created_at DESC, pr.post_id DESC
+	          LIMIT ?`
+
+	args := append(append([]interface{}{userID, post.ReactionLike}, visibleArgs...), keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var liked []post.LikedPost
+	for rows.Next() {
+		p := &post.Post{}
+		var likedAt time.Time
+		err := rows.Scan(&p.ID, &p.AuthorID, &p.Content, &p.MediaURL, &p.Likes, &p.Dislikes, &p.CreatedAt, &p.UpdatedAt, &p.EditedAt, &p.CommentsLocked, &p.PinnedCommentID, &p.Visibility, &likedAt)
+		if err != nil {
+			return nil, err
+		}
+		liked = append(liked, post.LikedPost{Post: p, LikedAt: likedAt})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, item := range liked {
+		hashtags, _ := r.getPostHashtags(ctx, item.ID)
+		item.Hashtags = hashtags
+	}
+
+	return liked, nil
 }
 
 func (r *PostRepositoryImpl) GetUserReactions(ctx context.Context, userID int64, postIDs []int64) (map[int64]string, error) {
@@ -358,3 +774,79 @@ func (r *PostRepositoryImpl) GetUserReactions(ctx context.Context, userID int64,
 
 	return reactions, nil
 }
+
+func lockPostCounters(ctx context.Context, tx *sql.Tx, postID int64) error {
+	result, err := tx.ExecContext(ctx, `UPDATE posts SET likes = likes WHERE id = ?`, postID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return post.ErrPostNotFound
+	}
+	return nil
+}
+
+func findReaction(ctx context.Context, tx *sql.Tx, userID, postID int64) (string, error) {
+	query := `SELECT reaction_type FROM post_reactions WHERE user_id = ? AND post_id = ?`
+	var reactionType string
+	err := tx.QueryRowContext(ctx, query, userID, postID).Scan(&reactionType)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return reactionType, err
+}
+
+func findReactionState(ctx context.Context, tx *sql.Tx, postID int64) (*post.ReactionState, error) {
+	state := &post.ReactionState{PostID: postID}
+	query := `SELECT likes, dislikes FROM posts WHERE id = ?`
+	if err := tx.QueryRowContext(ctx, query, postID).Scan(&state.Likes, &state.Dislikes); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func adjustReactionCounters(ctx context.Context, tx *sql.Tx, postID int64, previous, current string, kinds post.ReactionKinds) error {
+	if previous != "" {
+		query := `UPDATE post_reaction_counts SET count = count - 1 WHERE post_id = ? AND reaction_type = ?`
+		if _, err := tx.ExecContext(ctx, query, postID, previous); err != nil {
+			return err
+		}
+		query = `DELETE FROM post_reaction_counts WHERE post_id = ? AND reaction_type = ? AND count <= 0`
+		if _, err := tx.ExecContext(ctx, query, postID, previous); err != nil {
+			return err
+		}
+		if column := reactionCounterColumn(kinds, previous); column != "" {
+			query := fmt.Sprintf(`UPDATE posts SET %s = MAX(0, %s - 1) WHERE id = ?`, column, column)
+			if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if current != "" {
+		query := `INSERT INTO post_reaction_counts (post_id, reaction_type, count) VALUES (?, ?, 1)
+		          ON CONFLICT(post_id, reaction_type) DO UPDATE SET count = count + 1`
+		if _, err := tx.ExecContext(ctx, query, postID, current); err != nil {
+			return err
+		}
+		if column := reactionCounterColumn(kinds, current); column != "" {
+			query := fmt.Sprintf(`UPDATE posts SET %s = %s + 1 WHERE id = ?`, column, column)
+			if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func reactionCounterColumn(kinds post.ReactionKinds, reactionType string) string {
+	switch kinds.Sentiment(reactionType) {
+	case post.SentimentPositive:
+		return "likes"
+	case post.SentimentNegative:
+		return "dislikes"
+	default:
+		return ""
+	}
+}
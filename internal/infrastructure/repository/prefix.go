@@ -0,0 +1,20 @@
+package repository
+
+func prefixUpperBound(prefix string) string {
+	upper := []byte(prefix)
+	for i := len(upper) - 1; i >= 0; i-- {
+		if upper[i] < 0xff {
+			upper[i]++
+			return string(upper[:i+1])
+		}
+	}
+	return ""
+}
+
+func prefixRange(column, prefix string) (string, []interface{}) {
+	upper := prefixUpperBound(prefix)
+	if upper == "" {
+		return " AND " + column + " >= ?", []interface{}{prefix}
+	}
+	return " AND " + column + " >= ? AND " + column + " < ?", []interface{}{prefix, upper}
+}
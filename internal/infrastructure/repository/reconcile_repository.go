@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"socialmediafeed/internal/reconcile"
+	"strings"
+)
+
+type ReconcileRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReconcileRepository(db *sql.DB) reconcile.Repository {
+	return &ReconcileRepositoryImpl{db: db}
+}
+
+func (r *ReconcileRepositoryImpl) FindPostDrift(ctx context.Context, mapping reconcile.Mapping, afterID int64, limit int) (*reconcile.Batch, error) {
+	positive, positiveArgs := inList(mapping.Positive)
+	negative, negativeArgs := inList(mapping.Negative)
+
+	query := `SELECT p.id, p.likes, p.dislikes,
+	                 COALESCE(SUM(CASE WHEN pr.reaction_type IN ` + positive + ` THEN 1 ELSE 0 END), 0),
+	                 COALESCE(SUM(CASE WHEN pr.reaction_type IN ` + negative + ` THEN 1 ELSE 0 END), 0)
+	          FROM (SELECT id, likes, dislikes FROM posts WHERE id > ? ORDER BY id LIMIT ?) p
+	          LEFT JOIN post_reactions pr ON pr.post_id = p.id
+	          GROUP BY p.id, p.likes, p.dislikes
+	          ORDER BY p.id`
+
+	args := append(append(positiveArgs, negativeArgs...), afterID, limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := &reconcile.Batch{}
+	var firstID int64
+	for rows.Next() {
+		var postID int64
+		var likes, dislikes, actualLikes, actualDislikes int
+		if err := rows.Scan(&postID, &likes, &dislikes, &actualLikes, &actualDislikes); err != nil {
+			return nil, err
+		}
+
+		if batch.Checked == 0 {
+			firstID = postID
+		}
+		batch.Checked++
+		batch.LastID = postID
+
+		if likes != actualLikes {
+			batch.Discrepancies = append(batch.Discrepancies, reconcile.Discrepancy{
+				Counter: reconcile.CounterPostLikes, EntityID: postID, Stored: likes, Actual: actualLikes,
+			})
+		}
+		if dislikes != actualDislikes {
+			batch.Discrepancies = append(batch.Discrepancies, reconcile.Discrepancy{
+				Counter: reconcile.CounterPostDislikes, EntityID: postID, Stored: dislikes, Actual: actualDislikes,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if batch.Checked == 0 {
+		return batch, nil
+	}
+
+	query = `SELECT post_id, reaction_type, SUM(stored), SUM(actual) FROM (
+	             SELECT post_id, reaction_type, count AS stored, 0 AS actual
+	             FROM post_reaction_counts WHERE post_id BETWEEN ? AND ?
+	             UNION ALL
+	             SELECT post_id, reaction_type, 0, COUNT(*)
+	             FROM post_reactions WHERE post_id BETWEEN ? AND ?
+	             GROUP BY post_id, reaction_type
+	         )
+	         GROUP BY post_id, reaction_type
+	         HAVING SUM(stored) != SUM(actual)
+	         ORDER BY post_id, reaction_type`
+
+	rows, err = r.db.QueryContext(ctx, query, firstID, batch.LastID, firstID, batch.LastID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := reconcile.Discrepancy{Counter: reconcile.CounterReactionCount}
+		if err := rows.Scan(&d.EntityID, &d.Key, &d.Stored, &d.Actual); err != nil {
+			return nil, err
+		}
+		batch.Discrepancies = append(batch.Discrepancies, d)
+	}
+
+	return batch, rows.Err()
+}
+
+func (r *ReconcileRepositoryImpl) FindHashtagDrift(ctx context.Context, afterID int64, limit int) (*reconcile.Batch, error) {
+	query := `SELECT h.id, h.tag, h.usage_count,
+	                 (SELECT COUNT(*) FROM post_hashtags ph JOIN posts p ON p.id = ph.post_id WHERE ph.hashtag_id = h.id)
+	          FROM hashtags h
+	          WHERE h.id > ?
+	          ORDER BY h.id
+	          LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := &reconcile.Batch{}
+	for rows.Next() {
+		d := reconcile.Discrepancy{Counter: reconcile.CounterHashtagUsage}
+		if err := rows.Scan(&d.EntityID, &d.Key, &d.Stored, &d.Actual); err != nil {
+			return nil, err
+		}
+
+		batch.Checked++
+		batch.LastID = d.EntityID
+		if d.Stored != d.Actual {
+			batch.Discrepancies = append(batch.Discrepancies, d)
+		}
+	}
+
+	return batch, rows.Err()
+}
+
+func (r *ReconcileRepositoryImpl) Fix(ctx context.Context, mapping reconcile.Mapping, drift []reconcile.Discrepancy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range drift {
+		if err := fixDiscrepancy(ctx, tx, mapping, d); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func fixDiscrepancy(ctx context.Context, tx *sql.Tx, mapping reconcile.Mapping, d reconcile.Discrepancy) error {
+	switch d.Counter {
+	case reconcile.CounterPostLikes:
+		positive, positiveArgs := inList(mapping.Positive)
+		query := `UPDATE posts SET likes = (
+		              SELECT COUNT(*) FROM post_reactions WHERE post_id = ? AND reaction_type IN ` + positive + `
+		          ) WHERE id = ?`
+		args := append(append([]interface{}{d.EntityID}, positiveArgs...), d.EntityID)
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	case reconcile.CounterPostDislikes:
+		negative, negativeArgs := inList(mapping.Negative)
+		query := `UPDATE posts SET dislikes = (
+		              SELECT COUNT(*) FROM post_reactions WHERE post_id = ? AND reaction_type IN ` + negative + `
+		          ) WHERE id = ?`
+		args := append(append([]interface{}{d.EntityID}, negativeArgs...), d.EntityID)
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	case reconcile.CounterReactionCount:
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_reaction_counts WHERE post_id = ? AND reaction_type = ?`, d.EntityID, d.Key); err != nil {
+			return err
+		}
+		query := `INSERT INTO post_reaction_counts (post_id, reaction_type, count)
+		          SELECT ?, ?, COUNT(*) FROM post_reactions WHERE post_id = ? AND reaction_type = ?
+		          HAVING COUNT(*) > 0`
+		_, err := tx.ExecContext(ctx, query, d.EntityID, d.Key, d.EntityID, d.Key)
+		return err
+	case reconcile.CounterHashtagUsage:
+		query := `UPDATE hashtags SET usage_count = (
+		              SELECT COUNT(*) FROM post_hashtags ph JOIN posts p ON p.id = ph.post_id WHERE ph.hashtag_id = ?
+		          ) WHERE id = ?`
+		_, err := tx.ExecContext(ctx, query, d.EntityID, d.EntityID)
+		return err
+	default:
+		return nil
+	}
+}
+
+func inList(values []string) (string, []interface{}) {
+	if len(values) == 0 {
+		return "(NULL)", nil
+	}
+
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		placeholders[i] = "?"
+		args[i] = value
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")", args
+}
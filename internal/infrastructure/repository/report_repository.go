@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"socialmediafeed/internal/report"
+	"socialmediafeed/pkg/pagination"
+	"strings"
+	"time"
+)
+
+type ReportRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) report.Repository {
+	return &ReportRepositoryImpl{db: db}
+}
+
+func (r *ReportRepositoryImpl) FindTargetOwner(ctx context.Context, targetType report.TargetType, targetID int64) (int64, bool, error) {
+	var query string
+	switch targetType {
+	case report.TargetPost:
+		query = `SELECT author_id FROM posts WHERE id = ?`
+	case report.TargetComment:
+		query = `SELECT user_id FROM comments WHERE id = ? AND deleted_at IS NULL`
+	case report.TargetUser:
+		query = `SELECT id FROM users WHERE id = ?`
+	default:
+		return 0, false, report.ErrInvalidTarget
+	}
+
+	var ownerID int64
+	err := r.db.QueryRowContext(ctx, query, targetID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return ownerID, true, nil
+}
+
+func (r *ReportRepositoryImpl) Create(ctx context.Context, rep *report.Report, ownerID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM report_cases WHERE target_type = ? AND target_id = ? AND status = ?`,
+		rep.TargetType, rep.TargetID, report.StatusOpen,
+	).Scan(&rep.CaseID)
+	if err == sql.ErrNoRows {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO report_cases (target_type, target_id, target_owner_id, status, report_count, first_reported_at, last_reported_at)
+			 VALUES (?, ?, ?, ?, 0, ?, ?)`,
+			rep.TargetType, rep.TargetID, ownerID, report.StatusOpen, rep.CreatedAt, rep.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if rep.CaseID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM reports WHERE case_id = ? AND reporter_id = ?)`,
+		rep.CaseID, rep.ReporterID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return report.ErrAlreadyReported
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO reports (case_id, reporter_id, target_type, target_id, reason, details, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rep.CaseID, rep.ReporterID, rep.TargetType, rep.TargetID, rep.Reason, rep.Details, rep.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if rep.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE report_cases SET report_count = report_count + 1, last_reported_at = ? WHERE id = ?`,
+		rep.CreatedAt, rep.CaseID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const reportCaseColumns = `id, target_type, target_id, target_owner_id, status, report_count, first_reported_at,
+	          last_reported_at, resolution, resolved_by, resolution_note, resolved_at`
+
+func (r *ReportRepositoryImpl) FindQueue(ctx context.Context, status report.Status, targetType report.TargetType, cursor *pagination.Cursor, limit int) ([]report.Case, error) {
+	query := `SELECT ` + reportCaseColumns + ` FROM report_cases WHERE status = ?`
+	args := []interface{}{status}
+
+	if targetType != "" {
+		query += ` AND target_type = ?`
+		args = append(args, targetType)
+	}
+
+	if cursor != nil {
+		count, firstAt, err := report.ParseQueueCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND (report_count < ? OR (report_count = ? AND (first_reported_at > ? OR (first_reported_at = ? AND id > ?))))`
+		args = append(args, count, count, firstAt, firstAt, cursor.ID)
+	}
+
+	query += ` ORDER BY report_count DESC, first_reported_at ASC, id ASC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := make([]report.Case, 0)
+	for rows.Next() {
+		c, err := scanReportCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachReasons(ctx, cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (r *ReportRepositoryImpl) FindCase(ctx context.Context, id int64) (*report.Case, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+reportCaseColumns+` FROM report_cases WHERE id = ?`, id)
+	c, err := scanReportCase(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cases := []report.Case{*c}
+	if err := r.attachReasons(ctx, cases); err != nil {
+		return nil, err
+	}
+	return &cases[0], nil
+}
+
+func (r *ReportRepositoryImpl) FindReportsByCase(ctx context.Context, caseID int64) ([]report.Report, error) {
+	query := `SELECT id, case_id, reporter_id, target_type, target_id, reason, details, created_at
+	          FROM reports WHERE case_id = ? ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]report.Report, 0)
+	for rows.Next() {
+		var rep report.Report
+		if err := rows.Scan(&rep.ID, &rep.CaseID, &rep.ReporterID, &rep.TargetType, &rep.TargetID, &rep.Reason, &rep.Details, &rep.CreatedAt); err != nil {
+			return nil, err
+		}
+		reports = append(reports, rep)
+	}
+
+	return reports, rows.Err()
+}
+
+func (r *ReportRepositoryImpl) ClaimCase(ctx context.Context, caseID, moderatorID int64, now, staleBefore time.Time) (bool, error) {
+	query := `UPDATE report_cases SET claimed_by = ?, claimed_at = ?
+	          WHERE id = ? AND status = ? AND (claimed_by IS NULL OR claimed_at < ?)`
+
+	result, err := r.db.ExecContext(ctx, query, moderatorID, now, caseID, report.StatusOpen, staleBefore)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *ReportRepositoryImpl) ReleaseCase(ctx context.Context, caseID, moderatorID int64) error {
+	query := `UPDATE report_cases SET claimed_by = NULL, claimed_at = NULL
+	          WHERE id = ? AND status = ? AND claimed_by = ?`
+	_, err := r.db.ExecContext(ctx, query, caseID, report.StatusOpen, moderatorID)
+	return err
+}
+
+func (r *ReportRepositoryImpl) Resolve(ctx context.Context, c *report.Case, entry *report.LogEntry) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE report_cases
+	          SET status = ?, resolution = ?, resolved_by = ?, resolution_note = ?, resolved_at = ?
+	          WHERE id = ? AND status = ? AND claimed_by = ?`
+
+	result, err := tx.ExecContext(ctx, query, c.Status, c.Resolution, c.ResolvedBy, c.ResolutionNote, c.ResolvedAt, c.ID, report.StatusOpen, c.ResolvedBy)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	if err := insertModerationAction(ctx, tx, entry); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *ReportRepositoryImpl) LogAction(ctx context.Context, entry *report.LogEntry) error {
+	return insertModerationAction(ctx, r.db, entry)
+}
+
+func (r *ReportRepositoryImpl) FindActionLog(ctx context.Context, cursor *pagination.Cursor, limit int) ([]report.LogEntry, error) {
+	keyset, keysetArgs, err := keysetBefore("ma.created_at", "ma.id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ma.id, ma.moderator_id, COALESCE(u.username, ''), ma.action, ma.target_type, ma.target_id,
+	                 ma.case_id, ma.note, ma.created_at
+	          FROM moderation_actions ma
+	          LEFT JOIN users u ON u.id = ma.moderator_id
+	          WHERE 1 = 1` + keyset + `
+	          ORDER BY ma.created_at DESC, ma.id DESC
+	          LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(keysetArgs, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]report.LogEntry, 0)
+	for rows.Next() {
+		var e report.LogEntry
+		if err := rows.Scan(&e.ID, &e.ModeratorID, &e.Moderator, &e.Action, &e.TargetType, &e.TargetID, &e.CaseID, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+func insertModerationAction(ctx context.Context, db interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, entry *report.LogEntry) error {
+	result, err := db.ExecContext(ctx,
+		`INSERT INTO moderation_actions (moderator_id, action, target_type, target_id, case_id, note, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.ModeratorID, entry.Action, entry.TargetType, entry.TargetID, entry.CaseID, entry.Note, entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	entry.ID, err = result.LastInsertId()
+	return err
+}
+
+func (r *ReportRepositoryImpl) attachReasons(ctx context.Context, cases []report.Case) error {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(cases))
+	args := make([]interface{}, len(cases))
+	index := make(map[int64]int, len(cases))
+	for i := range cases {
+		placeholders[i] = "?"
+		args[i] = cases[i].ID
+		index[cases[i].ID] = i
+		cases[i].Reasons = make(map[report.Reason]int)
+	}
+
+	query := `SELECT case_id, reason, COUNT(*) FROM reports
+	          WHERE case_id IN (` + strings.Join(placeholders, ", ") + `)
+	          GROUP BY case_id, reason`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var caseID int64
+		var reason report.Reason
+		var count int
+		if err := rows.Scan(&caseID, &reason, &count); err != nil {
+			return err
+		}
+		cases[index[caseID]].Reasons[reason] = count
+	}
+
+	return rows.Err()
+}
+
+func scanReportCase(row interface{ Scan(...interface{}) error }) (*report.Case, error) {
+	var c report.Case
+	var resolution sql.NullString
+	err := row.Scan(&c.ID, &c.TargetType, &c.TargetID, &c.TargetOwnerID, &c.Status, &c.ReportCount, &c.FirstReportedAt,
+		&c.LastReportedAt, &resolution, &c.ResolvedBy, &c.ResolutionNote, &c.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Resolution = report.Action(resolution.String)
+	return &c, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/internal/search"
+	"strings"
+)
+
+type SearchRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) search.Repository {
+	return &SearchRepositoryImpl{db: db}
+}
+
+func (r *SearchRepositoryImpl) SearchPosts(ctx context.Context, q search.Query) ([]search.Result, error) {
+	query := `SELECT p.id, p.author_id, u.username, p.id, '', p.created_at,
+	                 snippet(posts_fts, 0, char(2), char(3), '…', 16), bm25(posts_fts)
+	          FROM posts_fts
+	          JOIN posts p ON p.id = posts_fts.rowid
+	          JOIN users u ON u.id = p.author_id
+	          WHERE posts_fts MATCH ?`
+	args := []interface{}{q.Match}
+
+	filters, filterArgs := postSearchFilters(q, "p")
+	query += filters + ` ORDER BY bm25(posts_fts) LIMIT ? OFFSET ?`
+	args = append(append(args, filterArgs...), q.Limit, q.Offset)
+
+	return r.queryResults(ctx, search.TypePosts, query, args...)
+}
+
+func (r *SearchRepositoryImpl) SearchComments(ctx context.Context, q search.Query) ([]search.Result, error) {
+	query := `SELECT c.id, c.user_id, u.username, c.post_id, '', c.created_at,
+	                 snippet(comments_fts, 0, char(2), char(3), '…', 16), bm25(comments_fts)
+	          FROM comments_fts
+	          JOIN comments c ON c.id = comments_fts.rowid
+	          JOIN posts p ON p.id = c.post_id
+	          JOIN users u ON u.id = c.user_id
+	          WHERE comments_fts MATCH ? AND c.deleted_at IS NULL`
+	args := []interface{}{q.Match}
+
+	filters, filterArgs := postSearchFilters(q, "c")
+	notBlocked, notBlockedArgs := notBlockedWith("c.user_id", q.ViewerID)
+	query += filters + notBlocked + ` ORDER BY bm25(comments_fts) LIMIT ? OFFSET ?`
+	args = append(append(append(args, filterArgs...), notBlockedArgs...), q.Limit, q.Offset)
+
+	return r.queryResults(ctx, search.TypeComments, query, args...)
+}
+
+func (r *SearchRepositoryImpl) SearchUsers(ctx context.Context, q search.Query) ([]search.Result, error) {
+	query := `SELECT u.id, u.id, u.username, 0, u.username, u.created_at,
+	                 snippet(users_fts, 0, char(2), char(3), '…', 8), bm25(users_fts)
+	          FROM users_fts
+	          JOIN users u ON u.id = users_fts.rowid
+	          WHERE users_fts MATCH ?`
+	args := []interface{}{q.Match}
+
+	filters, filterArgs := dateFilters(q, "u.created_at")
+	notBlocked, notBlockedArgs := notBlockedWith("u.id", q.ViewerID)
+	query += filters + notBlocked + ` ORDER BY bm25(users_fts) LIMIT ? OFFSET ?`
+	args = append(append(append(args, filterArgs...), notBlockedArgs...), q.Limit, q.Offset)
+
+	return r.queryResults(ctx, search.TypeUsers, query, args...)
+}
+
+func (r *SearchRepositoryImpl) SearchHashtags(ctx context.Context, q search.Query) ([]search.Result, error) {
+	query := `SELECT h.id, 0, '', 0, h.tag, h.created_at,
+	                 snippet(hashtags_fts, 0, char(2), char(3), '…', 8), bm25(hashtags_fts)
+	          FROM hashtags_fts
+	          JOIN hashtags h ON h.id = hashtags_fts.rowid
+	          WHERE hashtags_fts MATCH ?`
+	args := []interface{}{q.Match}
+
+	filters, filterArgs := dateFilters(q, "h.updated_at")
+	query += filters + ` ORDER BY bm25(hashtags_fts), h.usage_count DESC LIMIT ? OFFSET ?`
+	args = append(append(args, filterArgs...), q.Limit, q.Offset)
+
+	return r.queryResults(ctx, search.TypeHashtags, query, args...)
+}
+
+func (r *SearchRepositoryImpl) queryResults(ctx context.Context, resultType search.Type, query string, args ...interface{}) ([]search.Result, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []search.Result
+	for rows.Next() {
+		res := search.Result{Type: resultType}
+		var rank float64
+		err := rows.Scan(&res.ID, &res.AuthorID, &res.Author, &res.PostID, &res.Title, &res.CreatedAt, &res.Snippet, &rank)
+		if err != nil {
+			return nil, err
+		}
+		res.Score = -rank
+		results = append(results, res)
+	}
+
+	return results, rows.Err()
+}
+
+func postSearchFilters(q search.Query, alias string) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if q.Author != "" {
+		clauses = append(clauses, "u.username = ?")
+		args = append(args, strings.TrimPrefix(q.Author, "@"))
+	}
+	if q.Hashtag != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM post_hashtags ph
+		                                   JOIN hashtags h ON h.id = ph.hashtag_id
+		                                   WHERE ph.post_id = p.id AND h.tag = ?)`)
+		args = append(args, hashtag.NormalizeTag(q.Hashtag))
+	}
+
+	dates, dateArgs := dateFilters(q, alias+".created_at")
+	visible, visibleArgs := postVisibleTo("p", q.ViewerID)
+	filters := ""
+	for _, clause := range clauses {
+		filters += " AND " + clause
+	}
+
+	args = append(append(args, dateArgs...), visibleArgs...)
+	return filters + dates + visible, args
+}
+
+func dateFilters(q search.Query, column string) (string, []interface{}) {
+	filters := ""
+	var args []interface{}
+
+	if !q.From.IsZero() {
+		filters += " AND " + column + " >= ?"
+		args = append(args, q.From)
+	}
+	if !q.To.IsZero() {
+		filters += " AND " + column + " < ?"
+		args = append(args, q.To)
+	}
+
+	return filters, args
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"socialmediafeed/internal/user"
+	"socialmediafeed/pkg/pagination"
+	"strings"
+	"time"
 )
 
 type UserRepositoryImpl struct {
@@ -126,11 +129,16 @@ func (r *UserRepositoryImpl) CountUsers(ctx context.Context) (int, error) {
 	return count, err
 }
 
-func (r *UserRepositoryImpl) FindWithPagination(ctx context.Context, limit, offset int) ([]user.User, error) {
+func (r *UserRepositoryImpl) FindWithPagination(ctx context.Context, cursor *pagination.Cursor, limit int) ([]user.User, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `SELECT id, username, email, password_hash, role, created_at, updated_at 
-	          FROM users ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	          FROM users WHERE 1 = 1` + keyset + ` ORDER BY created_at DESC, id DESC LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, append(keysetArgs, limit)...)
 	if err != nil {
 		return nil, err
 	}
@@ -149,6 +157,34 @@ func (r *UserRepositoryImpl) FindWithPagination(ctx context.Context, limit, offs
 	return users, nil
 }
 
+func (r *UserRepositoryImpl) FindByRole(ctx context.Context, role user.Role, cursor *pagination.Cursor, limit int) ([]user.User, error) {
+	keyset, keysetArgs, err := keysetBefore("updated_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, username, email, password_hash, role, created_at, updated_at
+	          FROM users WHERE role = ?` + keyset + ` ORDER BY updated_at DESC, id DESC LIMIT ?`
+
+	args := append([]interface{}{role}, keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]user.User, 0)
+	for rows.Next() {
+		var u user.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
 func (r *UserRepositoryImpl) SearchByUsername(ctx context.Context, query string) ([]user.User, error) {
 	sqlQuery := `SELECT id, username, email, password_hash, role, created_at, updated_at 
 	             FROM users 
@@ -175,8 +211,587 @@ func (r *UserRepositoryImpl) SearchByUsername(ctx context.Context, query string)
 	return users, nil
 }
 
-func (r *UserRepositoryImpl) Ban(ctx context.Context, userID int64) error {
-	query := `UPDATE users SET role = 'banned' WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, userID)
+func (r *UserRepositoryImpl) AutocompleteByUsername(ctx context.Context, prefix string, limit int) ([]user.User, error) {
+	rangeClause, args := prefixRange("u.username COLLATE NOCASE", strings.ToLower(prefix))
+	sqlQuery := `SELECT u.id, u.username, u.email, u.password_hash, u.role, u.created_at, u.updated_at 
+	             FROM users u 
+	             WHERE u.role != 'banned'` + rangeClause + `
+	             ORDER BY (SELECT MAX(p.created_at) FROM posts p WHERE p.author_id = u.id) DESC, u.username ASC 
+	             LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []user.User
+	for rows.Next() {
+		var u user.User
+		err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepositoryImpl) GetPrivacySettings(ctx context.Context, userID int64) (*user.PrivacySettings, error) {
+	query := `SELECT show_likes, private_account FROM user_privacy_settings WHERE user_id = ?`
+
+	var settings user.PrivacySettings
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&settings.ShowLikes, &settings.PrivateAccount)
+	if err == sql.ErrNoRows {
+		return &settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *UserRepositoryImpl) SavePrivacySettings(ctx context.Context, userID int64, settings *user.PrivacySettings) error {
+	query := `INSERT INTO user_privacy_settings (user_id, show_likes, private_account, updated_at) VALUES (?, ?, ?, ?)
+	          ON CONFLICT(user_id) DO UPDATE SET show_likes = excluded.show_likes, private_account = excluded.private_account,
+	          updated_at = excluded.updated_at`
+	_, err := r.db.ExecContext(ctx, query, userID, settings.ShowLikes, settings.PrivateAccount, time.Now())
+	return err
+}
+
+func (r *UserRepositoryImpl) IsFollowing(ctx context.Context, followerID, followingID int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM followers WHERE follower_id = ? AND following_id = ?`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, followerID, followingID).Scan(&count)
+	return count > 0, err
+}
+
+func (r *UserRepositoryImpl) Follow(ctx context.Context, followerID, followingID int64) error {
+	query := `INSERT OR IGNORE INTO followers (follower_id, following_id, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, followerID, followingID, time.Now())
+	return err
+}
+
+func (r *UserRepositoryImpl) Unfollow(ctx context.Context, followerID, followingID int64) error {
+	query := `DELETE FROM followers WHERE follower_id = ? AND following_id = ?`
+	_, err := r.db.ExecContext(ctx, query, followerID, followingID)
+	return err
+}
+
+func (r *UserRepositoryImpl) CreateFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	query := `INSERT OR IGNORE INTO follow_requests (requester_id, target_id, created_at) VALUES (?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, requesterID, targetID, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *UserRepositoryImpl) DeleteFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	query := `DELETE FROM follow_requests WHERE requester_id = ? AND target_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, requesterID, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *UserRepositoryImpl) AcceptFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM follow_requests WHERE requester_id = ? AND target_id = ?`, requesterID, targetID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	query := `INSERT OR IGNORE INTO followers (follower_id, following_id, created_at) VALUES (?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, requesterID, targetID, time.Now()); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *UserRepositoryImpl) AcceptAllFollowRequests(ctx context.Context, targetID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT OR IGNORE INTO followers (follower_id, following_id, created_at)
+	          SELECT requester_id, target_id, ? FROM follow_requests WHERE target_id = ?`
+	if _, err := tx.ExecContext(ctx, query, time.Now(), targetID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM follow_requests WHERE target_id = ?`, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepositoryImpl) FindFollowRequests(ctx context.Context, targetID int64, cursor *pagination.Cursor, limit int) ([]user.FollowRequest, error) {
+	keyset, keysetArgs, err := keysetBefore("fr.created_at", "fr.requester_id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT fr.requester_id, u.username, fr.created_at
+	          FROM follow_requests fr
+	          JOIN users u ON u.id = fr.requester_id
+	          WHERE fr.target_id = ?` + keyset + ` ORDER BY fr.created_at DESC, fr.requester_id DESC LIMIT ?`
+
+	args := append([]interface{}{targetID}, keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []user.FollowRequest
+	for rows.Next() {
+		var fr user.FollowRequest
+		if err := rows.Scan(&fr.RequesterID, &fr.Username, &fr.CreatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, fr)
+	}
+
+	return requests, rows.Err()
+}
+
+func (r *UserRepositoryImpl) Block(ctx context.Context, blockerID, blockedID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT OR IGNORE INTO user_blocks (blocker_id, blocked_id, created_at) VALUES (?, ?, ?)`, []interface{}{blockerID, blockedID, time.Now()}},
+		{`DELETE FROM followers WHERE (follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)`, []interface{}{blockerID, blockedID, blockedID, blockerID}},
+		{`DELETE FROM follow_requests WHERE (requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)`, []interface{}{blockerID, blockedID, blockedID, blockerID}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepositoryImpl) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = ? AND blocked_id = ?`
+	_, err := r.db.ExecContext(ctx, query, blockerID, blockedID)
+	return err
+}
+
+func (r *UserRepositoryImpl) IsBlocked(ctx context.Context, userID, otherID int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM user_blocks
+	          WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, otherID, otherID, userID).Scan(&count)
+	return count > 0, err
+}
+
+func (r *UserRepositoryImpl) IsBlockedWithAny(ctx context.Context, userID int64, usernames []string) (bool, error) {
+	if len(usernames) == 0 {
+		return false, nil
+	}
+
+	placeholders := make([]string, len(usernames))
+	args := []interface{}{userID, userID}
+	for i, username := range usernames {
+		placeholders[i] = "?"
+		args = append(args, username)
+	}
+
+	query := `SELECT COUNT(*) FROM users u
+	          JOIN user_blocks b ON (b.blocker_id = ? AND b.blocked_id = u.id) OR (b.blocker_id = u.id AND b.blocked_id = ?)
+	          WHERE u.username COLLATE NOCASE IN (` + strings.Join(placeholders, ", ") + `)`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count > 0, err
+}
+
+func (r *UserRepositoryImpl) FindBlocked(ctx context.Context, blockerID int64, cursor *pagination.Cursor, limit int) ([]user.RelatedUser, error) {
+	return r.findRelated(ctx, "user_blocks", "blocker_id", "blocked_id", blockerID, cursor, limit)
+}
+
+func (r *UserRepositoryImpl) Mute(ctx context.Context, muterID, mutedID int64) error {
+	query := `INSERT OR IGNORE INTO user_mutes (muter_id, muted_id, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, muterID, mutedID, time.Now())
+	return err
+}
+
+func (r *UserRepositoryImpl) Unmute(ctx context.Context, muterID, mutedID int64) error {
+	query := `DELETE FROM user_mutes WHERE muter_id = ? AND muted_id = ?`
+	_, err := r.db.ExecContext(ctx, query, muterID, mutedID)
+	return err
+}
+
+func (r *UserRepositoryImpl) FindMuted(ctx context.Context, muterID int64, cursor *pagination.Cursor, limit int) ([]user.RelatedUser, error) {
+	return r.findRelated(ctx, "user_mutes", "muter_id", "muted_id", muterID, cursor, limit)
+}
+
+func (r *UserRepositoryImpl) findRelated(ctx context.Context, table, ownerColumn, otherColumn string, ownerID int64, cursor *pagination.Cursor, limit int) ([]user.RelatedUser, error) {
+	keyset, keysetArgs, err := keysetBefore("rel.created_at", "rel."+otherColumn, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT rel.` + otherColumn + `, u.username, rel.created_at
+	          FROM ` + table + ` rel
+	          JOIN users u ON u.id = rel.` + otherColumn + `
+	          WHERE rel.` + ownerColumn + ` = ?` + keyset + `
+	          ORDER BY rel.created_at DESC, rel.` + otherColumn + ` DESC LIMIT ?`
+
+	args := append([]interface{}{ownerID}, keysetArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var related []user.RelatedUser
+	for rows.Next() {
+		var ru user.RelatedUser
+		if err := rows.Scan(&ru.UserID, &ru.Username, &ru.CreatedAt); err != nil {
+			return nil, err
+		}
+		related = append(related, ru)
+	}
+
+	return related, rows.Err()
+}
+
+const activeSanction = `ended_at IS NULL AND (expires_at IS NULL OR expires_at > ?)`
+
+func (r *UserRepositoryImpl) CreateSanction(ctx context.Context, sanction *user.Sanction) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if sanction.LocksOut() {
+		var role string
+		if err := tx.QueryRowContext(ctx, `SELECT role FROM users WHERE id = ?`, sanction.UserID).Scan(&role); err != nil {
+			return err
+		}
+		if user.Role(role) == user.RoleBanned {
+			role, err = priorRole(ctx, tx, sanction.UserID)
+			if err != nil {
+				return err
+			}
+		}
+		sanction.PriorRole = user.Role(role)
+
+		query := `UPDATE users SET role = ?, updated_at = ? WHERE id = ?`
+		if _, err := tx.ExecContext(ctx, query, user.RoleBanned, sanction.CreatedAt, sanction.UserID); err != nil {
+			return err
+		}
+	}
+
+	query := `INSERT INTO user_sanctions (user_id, moderator_id, type, reason, prior_role, created_at, expires_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query, sanction.UserID, sanction.ModeratorID, sanction.Type, sanction.Reason, sanction.PriorRole, sanction.CreatedAt.UTC(), utcTime(sanction.ExpiresAt))
+	if err != nil {
+		return err
+	}
+	sanction.ID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepositoryImpl) FindActiveSanctions(ctx context.Context, userID int64, now time.Time) ([]user.Sanction, error) {
+	query := `SELECT id, user_id, moderator_id, type, reason, prior_role, created_at, expires_at, ended_at, revoked_by
+	          FROM user_sanctions
+	          WHERE user_id = ? AND ` + activeSanction + `
+	          ORDER BY created_at DESC, id DESC`
+
+	return r.querySanctions(ctx, query, userID, now.UTC())
+}
+
+func (r *UserRepositoryImpl) FindSanctions(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]user.Sanction, error) {
+	keyset, keysetArgs, err := keysetBefore("created_at", "id", cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, user_id, moderator_id, type, reason, prior_role, created_at, expires_at, ended_at, revoked_by
+	          FROM user_sanctions
+	          WHERE user_id = ?` + keyset + ` ORDER BY created_at DESC, id DESC LIMIT ?`
+
+	args := append([]interface{}{userID}, keysetArgs...)
+	return r.querySanctions(ctx, query, append(args, limit)...)
+}
+
+func (r *UserRepositoryImpl) querySanctions(ctx context.Context, query string, args ...interface{}) ([]user.Sanction, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sanctions := make([]user.Sanction, 0)
+	for rows.Next() {
+		var s user.Sanction
+		if err := rows.Scan(&s.ID, &s.UserID, &s.ModeratorID, &s.Type, &s.Reason, &s.PriorRole, &s.CreatedAt, &s.ExpiresAt, &s.EndedAt, &s.RevokedBy); err != nil {
+			return nil, err
+		}
+		sanctions = append(sanctions, s)
+	}
+
+	return sanctions, rows.Err()
+}
+
+func (r *UserRepositoryImpl) EndSanction(ctx context.Context, id, userID, revokedBy int64, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user_sanctions SET ended_at = ?, revoked_by = ?
+	          WHERE id = ? AND user_id = ? AND ` + activeSanction
+	now = now.UTC()
+	result, err := tx.ExecContext(ctx, query, now, revokedBy, id, userID, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	if err := restoreRole(ctx, tx, userID, now); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *UserRepositoryImpl) EndSanctions(ctx context.Context, userID int64, types []user.SanctionType, revokedBy int64, now time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(types)), ", ")
+	query := `UPDATE user_sanctions SET ended_at = ?, revoked_by = ?
+	          WHERE user_id = ? AND type IN (` + placeholders + `) AND ` + activeSanction
+
+	now = now.UTC()
+	args := []interface{}{now, revokedBy, userID}
+	for _, t := range types {
+		args = append(args, t)
+	}
+	result, err := tx.ExecContext(ctx, query, append(args, now)...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := restoreRole(ctx, tx, userID, now); err != nil {
+		return 0, err
+	}
+
+	return affected, tx.Commit()
+}
+
+func (r *UserRepositoryImpl) EndExpiredSanctions(ctx context.Context, now time.Time) (int64, error) {
+	return r.endExpiredSanctions(ctx, "", now)
+}
+
+func (r *UserRepositoryImpl) EndExpiredUserSanctions(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	return r.endExpiredSanctions(ctx, ` AND user_id = ?`, now, userID)
+}
+
+func (r *UserRepositoryImpl) endExpiredSanctions(ctx context.Context, filter string, now time.Time, filterArgs ...interface{}) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now = now.UTC()
+	expired := `ended_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?` + filter
+	args := append([]interface{}{now}, filterArgs...)
+
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT user_id FROM user_sanctions WHERE `+expired, args...)
+	if err != nil {
+		return 0, err
+	}
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE user_sanctions SET ended_at = expires_at WHERE `+expired, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		if err := restoreRole(ctx, tx, userID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	return affected, tx.Commit()
+}
+
+func priorRole(ctx context.Context, tx *sql.Tx, userID int64) (string, error) {
+	query := `SELECT prior_role FROM user_sanctions
+	          WHERE user_id = ? AND type IN ('ban', 'suspension') AND prior_role NOT IN ('', 'banned')
+	          ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	var role string
+	err := tx.QueryRowContext(ctx, query, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return string(user.RoleUser), nil
+	}
+	return role, err
+}
+
+func restoreRole(ctx context.Context, tx *sql.Tx, userID int64, now time.Time) error {
+	var locked int
+	query := `SELECT COUNT(*) FROM user_sanctions WHERE user_id = ? AND type IN ('ban', 'suspension') AND ` + activeSanction
+	if err := tx.QueryRowContext(ctx, query, userID, now).Scan(&locked); err != nil {
+		return err
+	}
+	if locked > 0 {
+		return nil
+	}
+
+	role, err := priorRole(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET role = ?, updated_at = ? WHERE id = ? AND role = ?`, role, now, userID, user.RoleBanned)
+	return err
+}
+
+func (r *UserRepositoryImpl) FindRolePermissions(ctx context.Context, role user.Role) ([]string, error) {
+	query := `SELECT permission FROM role_permissions WHERE role = ? ORDER BY permission`
+
+	rows, err := r.db.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func (r *UserRepositoryImpl) AddRolePermission(ctx context.Context, role user.Role, permission string) error {
+	query := `INSERT OR IGNORE INTO role_permissions (role, permission) VALUES (?, ?)`
+	_, err := r.db.ExecContext(ctx, query, role, permission)
 	return err
 }
+
+func (r *UserRepositoryImpl) RemoveRolePermission(ctx context.Context, role user.Role, permission string) (bool, error) {
+	query := `DELETE FROM role_permissions WHERE role = ? AND permission = ?`
+	result, err := r.db.ExecContext(ctx, query, role, permission)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *UserRepositoryImpl) FindPermissionOverrides(ctx context.Context, userID int64) ([]user.PermissionOverride, error) {
+	query := `SELECT user_id, permission, granted, granted_by, created_at
+	          FROM user_permission_overrides WHERE user_id = ? ORDER BY permission`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []user.PermissionOverride{}
+	for rows.Next() {
+		var o user.PermissionOverride
+		if err := rows.Scan(&o.UserID, &o.Permission, &o.Granted, &o.GrantedBy, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+
+	return overrides, rows.Err()
+}
+
+func (r *UserRepositoryImpl) SavePermissionOverride(ctx context.Context, o *user.PermissionOverride) error {
+	query := `INSERT INTO user_permission_overrides (user_id, permission, granted, granted_by, created_at)
+	          VALUES (?, ?, ?, ?, ?)
+	          ON CONFLICT(user_id, permission) DO UPDATE SET
+	              granted = excluded.granted, granted_by = excluded.granted_by, created_at = excluded.created_at`
+	_, err := r.db.ExecContext(ctx, query, o.UserID, o.Permission, o.Granted, o.GrantedBy, o.CreatedAt)
+	return err
+}
+
+func (r *UserRepositoryImpl) DeletePermissionOverride(ctx context.Context, userID int64, permission string) (bool, error) {
+	query := `DELETE FROM user_permission_overrides WHERE user_id = ? AND permission = ?`
+	result, err := r.db.ExecContext(ctx, query, userID, permission)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"socialmediafeed/internal/post"
+	"strings"
+)
+
+func postVisibleTo(alias string, viewerID int64) (string, []interface{}) {
+	notBlocked, notBlockedArgs := notBlockedWith(alias+".author_id", viewerID)
+	clause := ` AND (` + alias + `.author_id = ? OR ((
+	              NOT EXISTS (SELECT 1 FROM user_privacy_settings vp WHERE vp.user_id = ` + alias + `.author_id AND vp.private_account = 1)
+	              OR EXISTS (SELECT 1 FROM followers vf WHERE vf.follower_id = ? AND vf.following_id = ` + alias + `.author_id))
+	          AND (` + alias + `.visibility = ?
+	          OR (` + alias + `.visibility = ? AND EXISTS (
+	              SELECT 1 FROM followers vf WHERE vf.follower_id = ? AND vf.following_id = ` + alias + `.author_id))
+	          OR (` + alias + `.visibility = ? AND EXISTS (
+	              SELECT 1 FROM post_mentions vm WHERE vm.post_id = ` + alias + `.id AND vm.user_id = ?)))` + notBlocked + `))`
+
+	args := []interface{}{
+		viewerID, viewerID,
+		post.VisibilityPublic,
+		post.VisibilityFollowers, viewerID,
+		post.VisibilityMentioned, viewerID,
+	}
+	return clause, append(args, notBlockedArgs...)
+}
+
+func notBlockedWith(column string, viewerID int64) (string, []interface{}) {
+	clause := ` AND NOT EXISTS (SELECT 1 FROM user_blocks vb
+	              WHERE (vb.blocker_id = ? AND vb.blocked_id = ` + column + `) OR (vb.blocker_id = ` + column + ` AND vb.blocked_id = ?))`
+	return clause, []interface{}{viewerID, viewerID}
+}
+
+func notMutedBy(column string, viewerID int64) (string, []interface{}) {
+	clause := ` AND NOT EXISTS (SELECT 1 FROM user_mutes vu WHERE vu.muter_id = ? AND vu.muted_id = ` + column + `)`
+	return clause, []interface{}{viewerID}
+}
+
+func replacePostMentions(ctx context.Context, tx *sql.Tx, postID int64, usernames []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_mentions WHERE post_id = ?`, postID); err != nil {
+		return err
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(usernames))
+	args := []interface{}{postID}
+	for i, username := range usernames {
+		placeholders[i] = "?"
+		args = append(args, username)
+	}
+
+	query := `INSERT OR IGNORE INTO post_mentions (post_id, user_id)
+	          SELECT ?, id FROM users WHERE username COLLATE NOCASE IN (` + strings.Join(placeholders, ", ") + `)`
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
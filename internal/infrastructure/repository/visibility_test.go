@@ -0,0 +1,173 @@
+//go:build sqlite_fts5
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"socialmediafeed/internal/infrastructure/database"
+	"socialmediafeed/internal/post"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	author int64 = iota + 1
+	follower
+	stranger
+	mentioned
+	blocked
+	privateAuthor
+)
+
+type visibilityFixture struct {
+	db    *sql.DB
+	repo  post.PostRepository
+	posts map[string]int64
+}
+
+func newVisibilityFixture(t *testing.T) *visibilityFixture {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	users := []string{"author", "follower", "stranger", "mentioned", "blocked", "private"}
+	for i, username := range users {
+		exec(t, db, `INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, 'x')`,
+			i+1, username, username+"@example.com")
+	}
+
+	exec(t, db, `INSERT INTO user_privacy_settings (user_id, private_account, updated_at) VALUES (?, 1, ?)`, privateAuthor, now)
+	exec(t, db, `INSERT INTO followers (follower_id, following_id) VALUES (?, ?), (?, ?), (?, ?)`,
+		follower, author, follower, privateAuthor, blocked, author)
+	exec(t, db, `INSERT INTO user_blocks (blocker_id, blocked_id, created_at) VALUES (?, ?, ?)`, author, blocked, now)
+
+	f := &visibilityFixture{db: db, repo: NewPostRepository(db), posts: make(map[string]int64)}
+	f.createPost(t, "public", author, post.VisibilityPublic, now)
+	f.createPost(t, "followers", author, post.VisibilityFollowers, now.Add(time.Second))
+	f.createPost(t, "mentioned", author, post.VisibilityMentioned, now.Add(2*time.Second), "mentioned", "blocked")
+	f.createPost(t, "private public", privateAuthor, post.VisibilityPublic, now.Add(3*time.Second))
+	f.createPost(t, "private followers", privateAuthor, post.VisibilityFollowers, now.Add(4*time.Second))
+
+	exec(t, db, `INSERT INTO hashtag_follows (user_id, hashtag_id) SELECT u.id, h.id FROM users u, hashtags h WHERE h.tag = 'visibility'`)
+	return f
+}
+
+func (f *visibilityFixture) createPost(t *testing.T, name string, authorID int64, visibility post.Visibility, createdAt time.Time, mentions ...string) {
+	t.Helper()
+
+	p := &post.Post{
+		AuthorID:   authorID,
+		Content:    name + " #visibility",
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+		Visibility: visibility,
+		Hashtags:   []string{"visibility"},
+		Mentions:   mentions,
+	}
+	if err := f.repo.Create(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	f.posts[name] = p.ID
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostVisibleTo(t *testing.T) {
+	f := newVisibilityFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		viewer  int64
+		visible []string
+	}{
+		{"self", author, []string{"public", "followers", "mentioned"}},
+		{"anonymous", 0, []string{"public"}},
+		{"follower", follower, []string{"public", "followers", "private public", "private followers"}},
+		{"non-follower", stranger, []string{"public"}},
+		{"mentioned", mentioned, []string{"public", "mentioned"}},
+		{"blocked", blocked, nil},
+		{"private author", privateAuthor, []string{"public", "private public", "private followers"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[int64]bool)
+			for _, name := range tt.visible {
+				want[f.posts[name]] = true
+			}
+
+			for name, id := range f.posts {
+				p, err := f.repo.FindVisibleByID(ctx, id, tt.viewer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := p != nil; got != want[id] {
+					t.Errorf("FindVisibleByID(%q) visible = %v, want %v", name, got, want[id])
+				}
+			}
+
+			posts, err := f.repo.FindWithPagination(ctx, tt.viewer, nil, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]int64, len(posts))
+			for i, p := range posts {
+				ids[i] = p.ID
+			}
+			assertPostSet(t, "FindWithPagination", ids, want)
+
+			if tt.viewer == 0 {
+				return
+			}
+			items, err := f.repo.FindTimeline(ctx, tt.viewer, nil, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = make([]int64, len(items))
+			for i, item := range items {
+				ids[i] = item.ID
+			}
+			assertPostSet(t, "FindTimeline", ids, want)
+		})
+	}
+}
+
+func assertPostSet(t *testing.T, source string, got []int64, want map[int64]bool) {
+	t.Helper()
+
+	seen := make(map[int64]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("%s returned posts %v, want %v", source, got, keys(want))
+	}
+}
+
+func keys(m map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
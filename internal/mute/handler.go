@@ -0,0 +1,132 @@
+package mute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"socialmediafeed/pkg/responce"
+	"strconv"
+	"time"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) RegisterAuthenticatedRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/users/me/muted-words", auth(h.GetRules))
+	mux.HandleFunc("POST /api/users/me/muted-words", auth(h.AddRule))
+	mux.HandleFunc("DELETE /api/users/me/muted-words/{id}", auth(h.DeleteRule))
+}
+
+func (h *Handler) GetRules(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	rules, err := h.service.GetRules(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+}
+
+func (h *Handler) AddRule(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Pattern   string     `json:"pattern"`
+		Kind      Kind       `json:"kind"`
+		Action    Action     `json:"action"`
+		ExpiresAt *time.Time `json:"expires_at"`
+		ExpiresIn string     `json:"expires_in"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	if req.Kind == "" {
+		req.Kind = KindWord
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		at := req.ExpiresAt.UTC()
+		expiresAt = &at
+	}
+	if req.ExpiresIn != "" {
+		duration, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || duration <= 0 {
+			response.BadRequest(w, "Invalid expires_in, expected a positive duration such as 24h")
+			return
+		}
+		at := time.Now().Add(duration).UTC()
+		expiresAt = &at
+	}
+
+	rule, err := h.service.AddRule(r.Context(), userID, req.Pattern, req.Kind, req.Action, expiresAt)
+	if err != nil {
+		writeMuteError(w, err)
+		return
+	}
+
+	response.Created(w, rule)
+}
+
+func (h *Handler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid muted word ID")
+		return
+	}
+
+	if err := h.service.DeleteRule(r.Context(), id, userID); err != nil {
+		writeMuteError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func writeMuteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrRuleNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrInvalidKind), errors.Is(err, ErrInvalidAction),
+		errors.Is(err, ErrInvalidPattern), errors.Is(err, ErrInvalidExpiry),
+		errors.Is(err, ErrTooManyRules):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func getUserIDFromContext(ctx context.Context) int64 {
+	if userID, ok := ctx.Value("userID").(int64); ok {
+		return userID
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package mute
+
+import (
+	"regexp"
+	"socialmediafeed/internal/hashtag"
+	"strings"
+	"time"
+)
+
+const (
+	wordBoundaryStart = `(?:^|[^\pL\pN_])`
+	wordBoundaryEnd   = `(?:$|[^\pL\pN_])`
+)
+
+type compiledRule struct {
+	action Action
+	tag    string
+	re     *regexp.Regexp
+}
+
+type Matcher struct {
+	rules []compiledRule
+}
+
+func NewMatcher(rules []Rule, now time.Time) *Matcher {
+	m := &Matcher{}
+	for _, rule := range rules {
+		if rule.IsExpired(now) {
+			continue
+		}
+		compiled, err := compile(rule.Pattern, rule.Kind)
+		if err != nil {
+			continue
+		}
+		compiled.action = rule.Action
+		m.rules = append(m.rules, compiled)
+	}
+	return m
+}
+
+func (m *Matcher) Empty() bool {
+	return m == nil || len(m.rules) == 0
+}
+
+func (m *Matcher) Match(content string, hashtags []string) (Action, bool) {
+	if m.Empty() {
+		return "", false
+	}
+
+	var tags []string
+	matched := Action("")
+	for _, rule := range m.rules {
+		if rule.tag != "" {
+			if tags == nil {
+				tags = append(hashtag.ExtractTags(content), hashtags...)
+			}
+			if !containsTag(tags, rule.tag) {
+				continue
+			}
+		} else if !rule.re.MatchString(content) {
+			continue
+		}
+
+		if rule.action == ActionHide {
+			return ActionHide, true
+		}
+		matched = rule.action
+	}
+
+	return matched, matched != ""
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if hashtag.NormalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func compile(pattern string, kind Kind) (compiledRule, error) {
+	var expr string
+	switch kind {
+	case KindHashtag:
+		if !hashtag.IsValidTag(pattern) {
+			return compiledRule{}, ErrInvalidPattern
+		}
+		return compiledRule{tag: pattern}, nil
+	case KindWord:
+		if strings.ContainsAny(pattern, " \t") {
+			return compiledRule{}, ErrInvalidPattern
+		}
+		expr = regexp.QuoteMeta(pattern)
+	case KindPhrase:
+		words := strings.Fields(pattern)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		expr = strings.Join(words, `\s+`)
+	case KindRegex:
+		alternatives := strings.Split(pattern, "|")
+		for i, alt := range alternatives {
+			alt = strings.TrimSpace(alt)
+			if alt == "" {
+				return compiledRule{}, ErrInvalidPattern
+			}
+			alternatives[i] = translateRegexLite(alt)
+		}
+		expr = "(?:" + strings.Join(alternatives, "|") + ")"
+	default:
+		return compiledRule{}, ErrInvalidKind
+	}
+
+	re, err := regexp.Compile(`(?i)` + wordBoundaryStart + expr + wordBoundaryEnd)
+	if err != nil {
+		return compiledRule{}, ErrInvalidPattern
+	}
+	return compiledRule{re: re}, nil
+}
+
+func translateRegexLite(pattern string) string {
+	var b strings.Builder
+	for _, char := range pattern {
+		switch char {
+		case '*':
+			b.WriteString(`[\pL\pN_]*`)
+		case '?':
+			b.WriteString(`[\pL\pN_]`)
+		case ' ':
+			b.WriteString(`\s+`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	return b.String()
+}
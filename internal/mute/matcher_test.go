@@ -0,0 +1,110 @@
+package mute
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatcherKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		kind     Kind
+		content  string
+		hashtags []string
+		want     bool
+	}{
+		{"word", "spoiler", KindWord, "No SPOILER here!", nil, true},
+		{"word inside another word", "spoiler", KindWord, "spoilers ahead", nil, false},
+		{"phrase", "season finale", KindPhrase, "the season\n  finale was great", nil, true},
+		{"phrase out of order", "season finale", KindPhrase, "finale of the season", nil, false},
+		{"regex wildcard", "spoil*", KindRegex, "heavy spoilers ahead", nil, true},
+		{"regex single character", "c?t", KindRegex, "my cat", nil, true},
+		{"regex alternatives", "dune | foundation", KindRegex, "reading Foundation", nil, true},
+		{"regex no match", "spoil?", KindRegex, "spoilers", nil, false},
+		{"hashtag in content", "#GoLang", KindHashtag, "learning #golang today", nil, true},
+		{"hashtag on post", "golang", KindHashtag, "learning today", []string{"GoLang"}, true},
+		{"hashtag as plain word", "golang", KindHashtag, "learning golang today", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewRule(1, tt.pattern, tt.kind, ActionHide, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, got := NewMatcher([]Rule{*rule}, time.Now()).Match(tt.content, tt.hashtags)
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherActions(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	rules := []Rule{
+		{Pattern: "spoiler", Kind: KindWord, Action: ActionCollapse},
+		{Pattern: "leak", Kind: KindWord, Action: ActionHide},
+		{Pattern: "old", Kind: KindWord, Action: ActionHide, ExpiresAt: &expired},
+	}
+	m := NewMatcher(rules, now)
+
+	tests := []struct {
+		content string
+		action  Action
+		matched bool
+	}{
+		{"a spoiler", ActionCollapse, true},
+		{"a spoiler and a leak", ActionHide, true},
+		{"an old post", "", false},
+	}
+	for _, tt := range tests {
+		action, matched := m.Match(tt.content, nil)
+		if action != tt.action || matched != tt.matched {
+			t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.content, action, matched, tt.action, tt.matched)
+		}
+	}
+
+	if !NewMatcher(rules[2:], now).Empty() {
+		t.Error("matcher with only expired rules is not empty")
+	}
+}
+
+func TestNewRuleValidation(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		pattern   string
+		kind      Kind
+		action    Action
+		expiresAt *time.Time
+		want      error
+	}{
+		{"unknown kind", "x", Kind("glob"), ActionHide, nil, ErrInvalidKind},
+		{"unknown action", "x", KindWord, Action("delete"), nil, ErrInvalidAction},
+		{"empty pattern", "   ", KindWord, ActionHide, nil, ErrInvalidPattern},
+		{"word with spaces", "two words", KindWord, ActionHide, nil, ErrInvalidPattern},
+		{"empty alternative", "a||b", KindRegex, ActionHide, nil, ErrInvalidPattern},
+		{"expiry in the past", "x", KindWord, ActionHide, &past, ErrInvalidExpiry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRule(1, tt.pattern, tt.kind, tt.action, tt.expiresAt); err != tt.want {
+				t.Errorf("NewRule() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	rule, err := NewRule(1, "  Season   FINALE ", KindPhrase, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Pattern != "season finale" || rule.Action != ActionHide {
+		t.Errorf("NewRule() = %q, %q, want %q, %q", rule.Pattern, rule.Action, "season finale", ActionHide)
+	}
+}
@@ -0,0 +1,98 @@
+package mute
+
+import (
+	"errors"
+	"socialmediafeed/internal/hashtag"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindWord    Kind = "word"
+	KindPhrase  Kind = "phrase"
+	KindRegex   Kind = "regex"
+	KindHashtag Kind = "hashtag"
+)
+
+type Action string
+
+const (
+	ActionHide     Action = "hide"
+	ActionCollapse Action = "collapse"
+)
+
+const (
+	MaxRulesPerUser  = 100
+	MaxPatternLength = 100
+	Placeholder      = "[hidden by your muted words]"
+)
+
+var (
+	ErrInvalidKind    = errors.New("invalid kind, expected word, phrase, regex or hashtag")
+	ErrInvalidAction  = errors.New("invalid action, expected hide or collapse")
+	ErrInvalidPattern = errors.New("pattern must be between 1 and 100 characters")
+	ErrInvalidExpiry  = errors.New("expiry must be in the future")
+	ErrTooManyRules   = errors.New("muted word limit reached")
+	ErrRuleNotFound   = errors.New("muted word not found")
+)
+
+type Rule struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	Pattern   string     `json:"pattern"`
+	Kind      Kind       `json:"kind"`
+	Action    Action     `json:"action"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewRule(userID int64, pattern string, kind Kind, action Action, expiresAt *time.Time) (*Rule, error) {
+	switch kind {
+	case KindWord, KindPhrase, KindRegex, KindHashtag:
+	default:
+		return nil, ErrInvalidKind
+	}
+
+	if action == "" {
+		action = ActionHide
+	}
+	if action != ActionHide && action != ActionCollapse {
+		return nil, ErrInvalidAction
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidExpiry
+	}
+
+	pattern = normalizePattern(pattern, kind)
+	if pattern == "" || len(pattern) > MaxPatternLength {
+		return nil, ErrInvalidPattern
+	}
+	if _, err := compile(pattern, kind); err != nil {
+		return nil, err
+	}
+
+	return &Rule{
+		UserID:    userID,
+		Pattern:   pattern,
+		Kind:      kind,
+		Action:    action,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}, nil
+}
+
+func (r *Rule) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !r.ExpiresAt.After(now)
+}
+
+func normalizePattern(pattern string, kind Kind) string {
+	pattern = strings.ToLower(strings.Join(strings.Fields(pattern), " "))
+	if kind == KindHashtag {
+		return hashtag.NormalizeTag(pattern)
+	}
+	return pattern
+}
@@ -0,0 +1,14 @@
+package mute
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Save(ctx context.Context, rule *Rule) error
+	FindActiveByUser(ctx context.Context, userID int64, now time.Time) ([]Rule, error)
+	CountActiveByUser(ctx context.Context, userID int64, now time.Time) (int, error)
+	Delete(ctx context.Context, id, userID int64) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,85 @@
+package mute
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func (s *Service) AddRule(ctx context.Context, userID int64, pattern string, kind Kind, action Action, expiresAt *time.Time) (*Rule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rule, err := NewRule(userID, pattern, kind, action, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountActiveByUser(ctx, userID, rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxRulesPerUser {
+		return nil, ErrTooManyRules
+	}
+
+	if err := s.repo.Save(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save muted word: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (s *Service) GetRules(ctx context.Context, userID int64) ([]Rule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.FindActiveByUser(ctx, userID, time.Now())
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deleted, err := s.repo.Delete(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (s *Service) MatcherFor(ctx context.Context, userID int64) (*Matcher, error) {
+	if userID == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	rules, err := s.repo.FindActiveByUser(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return NewMatcher(rules, now), nil
+}
+
+func (s *Service) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return s.repo.DeleteExpired(ctx, time.Now())
+}
@@ -2,7 +2,9 @@ package notification
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"socialmediafeed/pkg/pagination"
 	response "socialmediafeed/pkg/responce"
 	"strconv"
 	"text/template"
@@ -21,13 +23,13 @@ func NewHandler(service *Service) *Handler {
 	}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("GET /api/notifications", h.GetNotifications)
-	mux.HandleFunc("GET /api/notifications/unread", h.GetUnreadNotifications)
-	mux.HandleFunc("GET /api/notifications/unread/count", h.GetUnreadCount)
-	mux.HandleFunc("PUT /api/notifications/{id}/read", h.MarkAsRead)
-	mux.HandleFunc("PUT /api/notifications/read-all", h.MarkAllAsRead)
-	mux.HandleFunc("DELETE /api/notifications/{id}", h.DeleteNotification)
+func (h *Handler) RegisterRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/notifications", auth(h.GetNotifications))
+	mux.HandleFunc("GET /api/notifications/unread", auth(h.GetUnreadNotifications))
+	mux.HandleFunc("GET /api/notifications/unread/count", auth(h.GetUnreadCount))
+	mux.HandleFunc("PUT /api/notifications/{id}/read", auth(h.MarkAsRead))
+	mux.HandleFunc("PUT /api/notifications/read-all", auth(h.MarkAllAsRead))
+	mux.HandleFunc("DELETE /api/notifications/{id}", auth(h.DeleteNotification))
 }
 
 func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
@@ -37,25 +39,23 @@ func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
-	}
-
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	notifications, err := h.service.GetUserNotifications(r.Context(), userID, limit, offset)
+	page, err := h.service.GetUserNotifications(r.Context(), userID, params)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, notifications)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) GetUnreadNotifications(w http.ResponseWriter, r *http.Request) {
@@ -12,6 +12,12 @@ const (
 	TypeFollow  NotificationType = "follow"
 	TypeMention NotificationType = "mention"
 	TypeReply   NotificationType = "reply"
+
+	TypeFollowRequest  NotificationType = "follow_request"
+	TypeFollowApproved NotificationType = "follow_approved"
+
+	TypeReportResolved    NotificationType = "report_resolved"
+	TypeModerationWarning NotificationType = "moderation_warning"
 )
 
 type Notification struct {
@@ -52,7 +58,8 @@ func (n *Notification) HasRelatedEntity() bool {
 
 func IsValidType(t NotificationType) bool {
 	switch t {
-	case TypeLike, TypeComment, TypeFollow, TypeMention, TypeReply:
+	case TypeLike, TypeComment, TypeFollow, TypeMention, TypeReply, TypeFollowRequest, TypeFollowApproved,
+		TypeReportResolved, TypeModerationWarning:
 		return true
 	default:
 		return false
@@ -2,13 +2,14 @@ package notification
 
 import (
 	"context"
+	"socialmediafeed/pkg/pagination"
 	"time"
 )
 
 type Repository interface {
 	Create(ctx context.Context, notification *Notification) error
 	FindByID(ctx context.Context, id int64) (*Notification, error)
-	FindByUser(ctx context.Context, userID int64, limit, offset int) ([]Notification, error)
+	FindByUser(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]Notification, error)
 	FindUnreadByUser(ctx context.Context, userID int64) ([]Notification, error)
 	MarkAsRead(ctx context.Context, id int64) error
 	MarkAllAsRead(ctx context.Context, userID int64) error
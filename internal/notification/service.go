@@ -3,12 +3,18 @@ package notification
 import (
 	"context"
 	"fmt"
+	"socialmediafeed/pkg/pagination"
 	"time"
 )
 
+type BlockPolicy interface {
+	IsBlocked(ctx context.Context, userID, otherID int64) (bool, error)
+}
+
 type Service struct {
 	repo     Repository
 	observer *NotificationSubject
+	blocks   BlockPolicy
 }
 
 func NewService(repo Repository) *Service {
@@ -22,6 +28,10 @@ func (s *Service) RegisterObserver(observer NotificationObserver) {
 	s.observer.Attach(observer)
 }
 
+func (s *Service) SetBlockPolicy(policy BlockPolicy) {
+	s.blocks = policy
+}
+
 func (s *Service) CreateNotification(ctx context.Context, userID int64, notifType NotificationType, title, message string) (*Notification, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -62,11 +72,18 @@ func (s *Service) CreateNotificationWithEntity(ctx context.Context, userID int64
 	return notification, nil
 }
 
-func (s *Service) GetUserNotifications(ctx context.Context, userID int64, limit, offset int) ([]Notification, error) {
+func (s *Service) GetUserNotifications(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[Notification], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindByUser(ctx, userID, limit, offset)
+	notifications, err := s.repo.FindByUser(ctx, userID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Notification]{}, err
+	}
+
+	return pagination.NewPage(notifications, params.Limit, func(n Notification) *pagination.Cursor {
+		return pagination.NewTimeCursor(n.CreatedAt, n.ID)
+	}), nil
 }
 
 func (s *Service) GetUnreadNotifications(ctx context.Context, userID int64) ([]Notification, error) {
@@ -135,37 +152,84 @@ func (s *Service) CleanupOldNotifications(ctx context.Context, olderThan time.Du
 	return s.repo.DeleteOld(ctx, olderThan)
 }
 
-func (s *Service) NotifyPostLike(ctx context.Context, authorID, postID int64, likerUsername string) error {
+func (s *Service) NotifyPostLike(ctx context.Context, authorID, postID, likerID int64, likerUsername string) error {
 	title := "New Like"
 	message := fmt.Sprintf("%s liked your post", likerUsername)
-	_, err := s.CreateNotificationWithEntity(ctx, authorID, TypeLike, title, message, postID, "post")
-	return err
+	return s.notifyFrom(ctx, likerID, authorID, TypeLike, title, message, postID, "post")
 }
 
-func (s *Service) NotifyPostComment(ctx context.Context, authorID, postID int64, commenterUsername string) error {
+func (s *Service) NotifyPostComment(ctx context.Context, authorID, postID, commenterID int64, commenterUsername string) error {
 	title := "New Comment"
 	message := fmt.Sprintf("%s commented on your post", commenterUsername)
-	_, err := s.CreateNotificationWithEntity(ctx, authorID, TypeComment, title, message, postID, "post")
-	return err
+	return s.notifyFrom(ctx, commenterID, authorID, TypeComment, title, message, postID, "post")
 }
 
 func (s *Service) NotifyFollow(ctx context.Context, targetUserID, followerID int64, followerUsername string) error {
 	title := "New Follower"
 	message := fmt.Sprintf("%s started following you", followerUsername)
-	_, err := s.CreateNotificationWithEntity(ctx, targetUserID, TypeFollow, title, message, followerID, "user")
-	return err
+	return s.notifyFrom(ctx, followerID, targetUserID, TypeFollow, title, message, followerID, "user")
+}
+
+func (s *Service) NotifyFollowRequest(ctx context.Context, targetUserID, requesterID int64, requesterUsername string) error {
+	title := "New Follow Request"
+	message := fmt.Sprintf("%s requested to follow you", requesterUsername)
+	return s.notifyFrom(ctx, requesterID, targetUserID, TypeFollowRequest, title, message, requesterID, "user")
 }
 
-func (s *Service) NotifyMention(ctx context.Context, mentionedUserID, postID int64, mentionerUsername string) error {
+func (s *Service) NotifyFollowApproved(ctx context.Context, requesterID, targetUserID int64, targetUsername string) error {
+	title := "Follow Request Approved"
+	message := fmt.Sprintf("%s approved your follow request", targetUsername)
+	return s.notifyFrom(ctx, targetUserID, requesterID, TypeFollowApproved, title, message, targetUserID, "user")
+}
+
+func (s *Service) NotifyMention(ctx context.Context, mentionedUserID, postID, mentionerID int64, mentionerUsername string) error {
 	title := "You were mentioned"
 	message := fmt.Sprintf("%s mentioned you in a post", mentionerUsername)
-	_, err := s.CreateNotificationWithEntity(ctx, mentionedUserID, TypeMention, title, message, postID, "post")
-	return err
+	return s.notifyFrom(ctx, mentionerID, mentionedUserID, TypeMention, title, message, postID, "post")
 }
 
-func (s *Service) NotifyReply(ctx context.Context, commentAuthorID, commentID int64, replierUsername string) error {
+func (s *Service) NotifyReply(ctx context.Context, commentAuthorID, commentID, replierID int64, replierUsername string) error {
 	title := "New Reply"
 	message := fmt.Sprintf("%s replied to your comment", replierUsername)
-	_, err := s.CreateNotificationWithEntity(ctx, commentAuthorID, TypeReply, title, message, commentID, "comment")
+	return s.notifyFrom(ctx, replierID, commentAuthorID, TypeReply, title, message, commentID, "comment")
+}
+
+func (s *Service) NotifyReportResolved(ctx context.Context, reporterID, reportID int64, action string) error {
+	title := "Report Reviewed"
+	message := "Thanks for your report. A moderator reviewed it and took action"
+	if action == "dismiss" {
+		message = "Thanks for your report. A moderator reviewed it and found no violation"
+	}
+	_, err := s.CreateNotificationWithEntity(ctx, reporterID, TypeReportResolved, title, message, reportID, "report")
+	return err
+}
+
+func (s *Service) NotifyModerationWarning(ctx context.Context, userID, targetID int64, targetType, note string) error {
+	subject := targetType
+	if targetType == "user" {
+		subject = "account"
+	}
+
+	title := "Community Guidelines Warning"
+	message := fmt.Sprintf("A moderator found that your %s breaks the community guidelines", subject)
+	if note != "" {
+		message = fmt.Sprintf("%s: %s", message, note)
+	}
+	_, err := s.CreateNotificationWithEntity(ctx, userID, TypeModerationWarning, title, message, targetID, targetType)
+	return err
+}
+
+func (s *Service) notifyFrom(ctx context.Context, actorID, userID int64, notifType NotificationType, title, message string, entityID int64, entityType string) error {
+	if s.blocks != nil {
+		blocked, err := s.blocks.IsBlocked(ctx, userID, actorID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return nil
+		}
+	}
+
+	_, err := s.CreateNotificationWithEntity(ctx, userID, notifType, title, message, entityID, entityType)
 	return err
 }
@@ -3,9 +3,12 @@ package post
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/pkg/pagination"
 	"socialmediafeed/pkg/responce"
+	"socialmediafeed/pkg/revision"
 	"strconv"
 	"text/template"
 )
@@ -30,18 +33,26 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("DELETE /api/posts/{id}", h.DeletePost)
 	mux.HandleFunc("GET /api/posts", h.GetAllPosts)
 	mux.HandleFunc("GET /api/feed", h.GetFeed)
+	mux.HandleFunc("GET /api/timeline", h.GetTimeline)
 	mux.HandleFunc("GET /api/trending", h.GetTrending)
 	mux.HandleFunc("GET /api/users/{authorId}/posts", h.GetPostsByAuthor)
 	mux.HandleFunc("GET /api/hashtags/{tag}/posts", h.GetPostsByHashtag)
 	mux.HandleFunc("POST /api/posts/{id}/like", h.LikePost)
 	mux.HandleFunc("POST /api/posts/{id}/dislike", h.DislikePost)
+	mux.HandleFunc("PUT /api/posts/{id}/reaction", h.SetReaction)
+	mux.HandleFunc("DELETE /api/posts/{id}/reaction", h.RemoveReaction)
+	mux.HandleFunc("GET /api/reactions/kinds", h.GetReactionKinds)
+	mux.HandleFunc("GET /api/posts/{id}/reactions", h.GetReactors)
+	mux.HandleFunc("GET /api/users/{id}/likes", h.GetLikedPosts)
 	mux.HandleFunc("POST /api/posts/{id}/filters", h.ApplyFilters)
+	mux.HandleFunc("PUT /api/posts/{id}/visibility", h.SetVisibility)
 }
 
 func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Content  string `json:"content"`
-		ImageURL string `json:"image_url,omitempty"`
+		Content    string `json:"content"`
+		ImageURL   string `json:"image_url,omitempty"`
+		Visibility string `json:"visibility,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -60,8 +71,12 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.service.CreatePost(r.Context(), userID, req.Content, req.ImageURL)
+	post, err := h.service.CreatePost(r.Context(), userID, req.Content, req.ImageURL, req.Visibility)
 	if err != nil {
+		if errors.Is(err, ErrMentionBlocked) || errors.Is(err, ErrAccountRestricted) {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.BadRequest(w, err.Error())
 		return
 	}
@@ -77,9 +92,14 @@ func (h *Handler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.service.GetPostByID(r.Context(), id)
+	post, err := h.service.GetPostByID(r.Context(), id, getUserIDFromContext(r.Context()))
 	if err != nil {
-		response.NotFound(w, "Post not found")
+		writePostError(w, err)
+		return
+	}
+
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), post); err != nil {
+		response.InternalServerError(w, err.Error())
 		return
 	}
 
@@ -103,6 +123,7 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Content  string `json:"content,omitempty"`
 		ImageURL string `json:"image_url,omitempty"`
+		Reason   string `json:"reason,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -111,8 +132,12 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userRole := getUserRoleFromContext(r.Context())
-	post, err := h.service.UpdatePost(r.Context(), id, userID, req.Content, req.ImageURL, userRole)
+	post, err := h.service.UpdatePost(r.Context(), id, userID, req.Content, req.ImageURL, userRole, req.Reason)
 	if err != nil {
+		if errors.Is(err, revision.ErrEditWindowClosed) || errors.Is(err, ErrMentionBlocked) || errors.Is(err, ErrAccountRestricted) {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.BadRequest(w, err.Error())
 		return
 	}
@@ -134,8 +159,7 @@ func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userRole := getUserRoleFromContext(r.Context())
-	if err := h.service.DeletePost(r.Context(), id, userID, userRole); err != nil {
+	if err := h.service.DeletePost(r.Context(), id, userID); err != nil {
 		response.Forbidden(w, err.Error())
 		return
 	}
@@ -144,26 +168,28 @@ func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 20
-	offset := 0
-
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
-	}
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	posts, err := h.service.GetAllPosts(r.Context(), limit, offset)
+	page, err := h.service.GetAllPosts(r.Context(), getUserIDFromContext(r.Context()), params)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, posts)
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), page.Items...); err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
@@ -172,40 +198,48 @@ func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
 		sortBy = "date"
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	page, err := h.service.GetFeed(r.Context(), getUserIDFromContext(r.Context()), sortBy, params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
 	}
 
-	posts, err := h.service.GetFeed(r.Context(), sortBy, limit, offset)
-	if err != nil {
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), page.Items...); err != nil {
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, posts)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) GetTrending(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	posts, err := h.service.GetTrendingPosts(r.Context(), limit)
+	posts, err := h.service.GetTrendingPosts(r.Context(), getUserIDFromContext(r.Context()), limit)
 	if err != nil {
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), posts...); err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
 	response.JSON(w, http.StatusOK, posts)
 }
 
@@ -217,53 +251,59 @@ func (h *Handler) GetPostsByAuthor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	page, err := h.service.GetPostsByAuthor(r.Context(), authorID, getUserIDFromContext(r.Context()), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
 	}
 
-	posts, err := h.service.GetPostsByAuthor(r.Context(), authorID, limit, offset)
-	if err != nil {
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), page.Items...); err != nil {
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, posts)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) GetPostsByHashtag(w http.ResponseWriter, r *http.Request) {
 	tag := r.PathValue("tag")
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
-	}
-
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
 	hashtagObj := &hashtag.Hashtag{
 		Tag: tag,
 	}
 
-	posts, err := h.service.GetPostsByHashtag(r.Context(), hashtagObj, limit, offset)
+	page, err := h.service.GetPostsByHashtag(r.Context(), hashtagObj, getUserIDFromContext(r.Context()), params)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, posts)
+	if err := h.service.AttachReactions(r.Context(), getUserIDFromContext(r.Context()), page.Items...); err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
 func (h *Handler) LikePost(w http.ResponseWriter, r *http.Request) {
@@ -280,12 +320,8 @@ func (h *Handler) LikePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.LikePost(r.Context(), userID, id); err != nil {
-		if err.Error() == "you have already liked this post" {
-			response.BadRequest(w, err.Error())
-		} else {
-			response.InternalServerError(w, err.Error())
-		}
+	if _, err := h.service.SetReaction(r.Context(), userID, id, ReactionLike); err != nil {
+		writeReactionError(w, err)
 		return
 	}
 
@@ -306,18 +342,126 @@ func (h *Handler) DislikePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DislikePost(r.Context(), userID, id); err != nil {
-		if err.Error() == "you have already disliked this post" {
-			response.BadRequest(w, err.Error())
-		} else {
-			response.InternalServerError(w, err.Error())
-		}
+	if _, err := h.service.SetReaction(r.Context(), userID, id, ReactionDislike); err != nil {
+		writeReactionError(w, err)
 		return
 	}
 
 	response.Success(w, "Post disliked successfully")
 }
 
+func (h *Handler) SetReaction(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	state, err := h.service.SetReaction(r.Context(), userID, id, req.Type)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, state)
+}
+
+func (h *Handler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	state, err := h.service.RemoveReaction(r.Context(), userID, id)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, state)
+}
+
+func (h *Handler) GetReactionKinds(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, h.service.ReactionKinds())
+}
+
+func (h *Handler) GetReactors(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetReactors(r.Context(), id, getUserIDFromContext(r.Context()), r.URL.Query().Get("type"), params)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) GetLikedPosts(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	viewerID := getUserIDFromContext(r.Context())
+	page, err := h.service.GetLikedPosts(r.Context(), ownerID, viewerID, params)
+	if err != nil {
+		writeReactionError(w, err)
+		return
+	}
+
+	posts := make([]*Post, len(page.Items))
+	for i, item := range page.Items {
+		posts[i] = item.Post
+	}
+
+	if err := h.service.AttachReactions(r.Context(), viewerID, posts...); err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
 func (h *Handler) ApplyFilters(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -335,15 +479,253 @@ func (h *Handler) ApplyFilters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.service.ApplyFilters(r.Context(), id, req.Filters)
+	post, err := h.service.ApplyFilters(r.Context(), id, getUserIDFromContext(r.Context()), req.Filters)
+	if err != nil {
+		writePostError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, post)
+}
+
+func (h *Handler) SetVisibility(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req struct {
+		Visibility string `json:"visibility"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Visibility == "" {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	post, err := h.service.SetVisibility(r.Context(), id, userID, req.Visibility)
 	if err != nil {
+		writePostError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, post)
+}
+
+func writePostError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPostNotFound):
 		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrVisibilityForbidden):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrInvalidVisibility):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func (h *Handler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetTimeline(r.Context(), userID, params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	posts := make([]*Post, len(page.Items))
+	for i, item := range page.Items {
+		posts[i] = item.Post
+	}
+
+	if err := h.service.AttachReactions(r.Context(), userID, posts...); err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) LockComments(w http.ResponseWriter, r *http.Request) {
+	h.setCommentsLocked(w, r, true)
+}
+
+func (h *Handler) UnlockComments(w http.ResponseWriter, r *http.Request) {
+	h.setCommentsLocked(w, r, false)
+}
+
+func (h *Handler) setCommentsLocked(w http.ResponseWriter, r *http.Request, locked bool) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	post, err := h.service.SetCommentsLocked(r.Context(), id, userID, locked)
+	if err != nil {
+		writeThreadError(w, err)
 		return
 	}
 
 	response.JSON(w, http.StatusOK, post)
 }
 
+func (h *Handler) PinComment(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req struct {
+		CommentID int64 `json:"comment_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CommentID <= 0 {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	post, err := h.service.SetPinnedComment(r.Context(), id, userID, &req.CommentID)
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, post)
+}
+
+func (h *Handler) UnpinComment(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	post, err := h.service.SetPinnedComment(r.Context(), id, userID, nil)
+	if err != nil {
+		writeThreadError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, post)
+}
+
+func writeThreadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrThreadForbidden):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrCommentNotOnPost):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func (h *Handler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	history, err := h.service.GetRevisions(r.Context(), id, getUserIDFromContext(r.Context()))
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, history)
+}
+
+func (h *Handler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	from, to, err := revision.RangeFromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, getUserIDFromContext(r.Context()), from, to)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, diff)
+}
+
+func writeRevisionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPostNotFound), errors.Is(err, revision.ErrVersionNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrPostNotVisible):
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func writeReactionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrLikesHidden), errors.Is(err, ErrPostNotVisible), errors.Is(err, ErrAccountRestricted):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrInvalidReaction), errors.Is(err, pagination.ErrInvalidCursor):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
 func getUserIDFromContext(ctx context.Context) int64 {
 	if userID, ok := ctx.Value("userID").(int64); ok {
 		return userID
@@ -0,0 +1,121 @@
+package post
+
+import (
+	"context"
+	"socialmediafeed/internal/mute"
+	"socialmediafeed/pkg/pagination"
+)
+
+type MutedWordPolicy interface {
+	MatcherFor(ctx context.Context, userID int64) (*mute.Matcher, error)
+}
+
+func (s *Service) SetMutedWordPolicy(policy MutedWordPolicy) {
+	s.muted = policy
+}
+
+func (p *Post) Collapse() {
+	p.Content = mute.Placeholder
+	p.MediaURL = ""
+	p.Hashtags = []string{}
+	p.Mentions = nil
+	p.Collapsed = true
+}
+
+const maxMutedScans = 5
+
+func (s *Service) mutedPostPage(ctx context.Context, viewerID int64, params pagination.Params, cursorFor func(*Post) *pagination.Cursor, fetch func(cursor *pagination.Cursor) ([]*Post, error)) (pagination.Page[*Post], error) {
+	keep, err := s.mutedWordFilter(ctx, viewerID)
+	if err != nil {
+		return pagination.Page[*Post]{}, err
+	}
+	return fillPage(params, keep, cursorFor, fetch)
+}
+
+func (s *Service) mutedTimelinePage(ctx context.Context, viewerID int64, params pagination.Params, fetch func(cursor *pagination.Cursor) ([]TimelineItem, error)) (pagination.Page[TimelineItem], error) {
+	keepPost, err := s.mutedWordFilter(ctx, viewerID)
+	if err != nil {
+		return pagination.Page[TimelineItem]{}, err
+	}
+
+	var keep func(TimelineItem) bool
+	if keepPost != nil {
+		keep = func(item TimelineItem) bool {
+			return keepPost(item.Post)
+		}
+	}
+	cursorFor := func(item TimelineItem) *pagination.Cursor {
+		return postTimeCursor(item.Post)
+	}
+	return fillPage(params, keep, cursorFor, fetch)
+}
+
+func fillPage[T any](params pagination.Params, keep func(T) bool, cursorFor func(T) *pagination.Cursor, fetch func(cursor *pagination.Cursor) ([]T, error)) (pagination.Page[T], error) {
+	if keep == nil {
+		items, err := fetch(params.Cursor)
+		if err != nil {
+			return pagination.Page[T]{}, err
+		}
+		return pagination.NewPage(items, params.Limit, cursorFor), nil
+	}
+
+	page := pagination.Page[T]{Items: []T{}}
+	cursor := params.Cursor
+	for scan := 0; scan < maxMutedScans; scan++ {
+		batch, err := fetch(cursor)
+		if err != nil {
+			return pagination.Page[T]{}, err
+		}
+
+		for i, item := range batch {
+			if !keep(item) {
+				continue
+			}
+			page.Items = append(page.Items, item)
+			if len(page.Items) == params.Limit {
+				if i < len(batch)-1 || len(batch) == params.FetchLimit() {
+					page.NextCursor = cursorFor(item).Encode()
+				}
+				return page, nil
+			}
+		}
+
+		if len(batch) < params.FetchLimit() {
+			return page, nil
+		}
+		cursor = cursorFor(batch[len(batch)-1])
+	}
+
+	page.NextCursor = cursor.Encode()
+	return page, nil
+}
+
+func (s *Service) mutedWordFilter(ctx context.Context, viewerID int64) (func(*Post) bool, error) {
+	if s.muted == nil || viewerID == 0 {
+		return nil, nil
+	}
+
+	matcher, err := s.muted.MatcherFor(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if matcher.Empty() {
+		return nil, nil
+	}
+
+	return func(p *Post) bool {
+		if p.IsOwnedBy(viewerID) {
+			return true
+		}
+
+		action, matched := matcher.Match(p.Content, p.Hashtags)
+		switch {
+		case !matched:
+		case action == mute.ActionHide:
+			return false
+		default:
+			p.Collapse()
+		}
+		return true
+	}, nil
+}
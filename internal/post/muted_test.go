@@ -0,0 +1,75 @@
+package post
+
+import (
+	"reflect"
+	"socialmediafeed/pkg/pagination"
+	"testing"
+)
+
+func TestFillPage(t *testing.T) {
+	pagination.Configure("test-secret", 0, 0)
+
+	ids := make([]int64, 20)
+	for i := range ids {
+		ids[i] = int64(20 - i)
+	}
+	fetch := func(limit int) func(*pagination.Cursor) ([]int64, error) {
+		return func(cursor *pagination.Cursor) ([]int64, error) {
+			rest := ids
+			if cursor != nil {
+				for i, id := range ids {
+					if id == cursor.ID {
+						rest = ids[i+1:]
+					}
+				}
+			}
+			if len(rest) > limit {
+				rest = rest[:limit]
+			}
+			return rest, nil
+		}
+	}
+	cursorFor := func(id int64) *pagination.Cursor {
+		return pagination.NewCursor("", id)
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		keep     func(int64) bool
+		want     []int64
+		nextFrom int64
+	}{
+		{"no filter", 3, nil, []int64{20, 19, 18}, 18},
+		{"refills hidden rows", 4, func(id int64) bool { return id%3 == 0 }, []int64{18, 15, 12, 9}, 9},
+		{"last page", 4, func(id int64) bool { return id <= 2 }, []int64{2, 1}, 0},
+		{"stops after max scans", 2, func(id int64) bool { return false }, []int64{}, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := pagination.Params{Limit: tt.limit}
+			page, err := fillPage(params, tt.keep, cursorFor, fetch(params.FetchLimit()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page.Items, tt.want) {
+				t.Errorf("items = %v, want %v", page.Items, tt.want)
+			}
+
+			if tt.nextFrom == 0 {
+				if page.NextCursor != "" {
+					t.Errorf("next cursor = %q, want none", page.NextCursor)
+				}
+				return
+			}
+			next, err := pagination.Decode(page.NextCursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.ID != tt.nextFrom {
+				t.Errorf("next cursor ID = %d, want %d", next.ID, tt.nextFrom)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package post
 import (
 	"context"
 	"fmt"
+	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/pkg/permission"
 	"socialmediafeed/pkg/types"
 	"strings"
 	"time"
@@ -11,28 +13,40 @@ import (
 const MaxContentLength = 10000
 
 type Post struct {
-	ID        int64     `json:"id" db:"id"`
-	AuthorID  int64     `json:"author_id" db:"author_id"`
-	Content   string    `json:"content" db:"content"`
-	MediaURL  string    `json:"media_url" db:"media_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	Likes     int       `json:"likes" db:"likes"`
-	Dislikes  int       `json:"dislike" db:"dislikes"`
-	Hashtags  []string
+	ID              int64      `json:"id" db:"id"`
+	AuthorID        int64      `json:"author_id" db:"author_id"`
+	Content         string     `json:"content" db:"content"`
+	MediaURL        string     `json:"media_url" db:"media_url"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	EditedAt        *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	CommentsLocked  bool       `json:"comments_locked" db:"comments_locked"`
+	PinnedCommentID *int64     `json:"pinned_comment_id,omitempty" db:"pinned_comment_id"`
+	Visibility      Visibility `json:"visibility" db:"visibility"`
+	Likes           int        `json:"likes" db:"likes"`
+	Dislikes        int        `json:"dislike" db:"dislikes"`
+	Hashtags        []string
+	Mentions        []string `json:"-"`
+
+	Reactions      []ReactionCount `json:"reactions"`
+	ViewerReaction string          `json:"viewer_reaction,omitempty"`
+	Collapsed      bool            `json:"collapsed,omitempty"`
+	Score          float64         `json:"-"`
 }
 
 func NewPost(author int64, content, mediaUrl string) *Post {
-	hashtags := make([]string, 0)
+	hashtags := hashtag.ExtractTags(content)
 	return &Post{
-		AuthorID:  author,
-		Content:   content,
-		MediaURL:  mediaUrl,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Likes:     0,
-		Dislikes:  0,
-		Hashtags:  hashtags,
+		AuthorID:   author,
+		Content:    content,
+		MediaURL:   mediaUrl,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Likes:      0,
+		Dislikes:   0,
+		Hashtags:   hashtags,
+		Mentions:   ExtractMentions(content),
+		Visibility: VisibilityPublic,
 	}
 }
 
@@ -43,12 +57,12 @@ func (p *Post) IsOwnedBy(userID int64) bool {
 	return false
 }
 
-func (p *Post) CanBeEditedBy(userID int64, role string) bool {
-	return p.IsOwnedBy(userID) || role == "admin" || role == "moderator"
+func (p *Post) CanBeEditedBy(userID int64, permissions []string) bool {
+	return p.IsOwnedBy(userID) || permission.Has(permissions, permission.PostEditAny)
 }
 
-func (p *Post) CanBeDeletedBy(userID int64, role string) bool {
-	return p.IsOwnedBy(userID) || role == "admin" || role == "moderator"
+func (p *Post) CanBeDeletedBy(userID int64, permissions []string) bool {
+	return p.IsOwnedBy(userID) || permission.Has(permissions, permission.PostDeleteAny)
 }
 
 func (p *Post) IsValid() error {
@@ -69,6 +83,10 @@ func (p *Post) IsWithinContentLimit() bool {
 	return len(p.Content) <= MaxContentLength
 }
 
+func (p *Post) IsEdited() bool {
+	return p.EditedAt != nil
+}
+
 func (p *Post) HasHashtags() bool {
 	return len(p.Hashtags) > 0
 }
@@ -89,3 +107,28 @@ func (p *Post) ToFeedItem(ctx context.Context) types.FeedItem {
 		PostID:  fmt.Sprintf("%d", p.ID),
 	}
 }
+
+const (
+	ReasonOwnPost         = "own"
+	ReasonFollowedUser    = "followed_user"
+	ReasonFollowedHashtag = "followed_hashtag"
+)
+
+type TimelineItem struct {
+	*Post
+	ReasonType string `json:"reason_type"`
+	Reason     string `json:"reason"`
+}
+
+func NewTimelineItem(p *Post, reasonType, subject string) TimelineItem {
+	item := TimelineItem{Post: p, ReasonType: reasonType}
+	switch reasonType {
+	case ReasonFollowedUser:
+		item.Reason = "because you follow @" + subject
+	case ReasonFollowedHashtag:
+		item.Reason = "because you follow #" + subject
+	default:
+		item.Reason = "your post"
+	}
+	return item
+}
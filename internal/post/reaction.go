@@ -0,0 +1,145 @@
+package post
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+)
+
+type Sentiment string
+
+const (
+	SentimentPositive Sentiment = "positive"
+	SentimentNegative Sentiment = "negative"
+	SentimentNeutral  Sentiment = "neutral"
+)
+
+var (
+	ErrPostNotFound         = errors.New("post not found")
+	ErrPostNotVisible       = errors.New("you do not have access to this post")
+	ErrInvalidReaction      = errors.New("invalid reaction type")
+	ErrInvalidReactionKinds = errors.New("invalid reaction kinds")
+	ErrLikesHidden          = errors.New("this user's likes are private")
+)
+
+type ReactionKind struct {
+	Type      string    `json:"type"`
+	Emoji     string    `json:"emoji"`
+	Sentiment Sentiment `json:"sentiment"`
+}
+
+type ReactionKinds []ReactionKind
+
+var DefaultReactionKinds = ReactionKinds{
+	{Type: ReactionLike, Emoji: "👍", Sentiment: SentimentPositive},
+	{Type: ReactionDislike, Emoji: "👎", Sentiment: SentimentNegative},
+	{Type: "love", Emoji: "❤️", Sentiment: SentimentPositive},
+	{Type: "laugh", Emoji: "😂", Sentiment: SentimentPositive},
+	{Type: "wow", Emoji: "😮", Sentiment: SentimentNeutral},
+	{Type: "sad", Emoji: "😢", Sentiment: SentimentNeutral},
+	{Type: "angry", Emoji: "😠", Sentiment: SentimentNegative},
+}
+
+type ReactionCount struct {
+	Type  string `json:"type"`
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+}
+
+type ReactionState struct {
+	PostID    int64           `json:"post_id"`
+	Reaction  string          `json:"reaction"`
+	Previous  string          `json:"-"`
+	Likes     int             `json:"likes"`
+	Dislikes  int             `json:"dislikes"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+type Reactor struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Reaction  string    `json:"reaction"`
+	ReactedAt time.Time `json:"reacted_at"`
+}
+
+type LikedPost struct {
+	*Post
+	LikedAt time.Time `json:"liked_at"`
+}
+
+func (k ReactionKinds) Find(reactionType string) (ReactionKind, bool) {
+	for _, kind := range k {
+		if kind.Type == reactionType {
+			return kind, true
+		}
+	}
+	return ReactionKind{}, false
+}
+
+func (k ReactionKinds) Sentiment(reactionType string) Sentiment {
+	if kind, ok := k.Find(reactionType); ok {
+		return kind.Sentiment
+	}
+	return SentimentNeutral
+}
+
+func (k ReactionKinds) Summary(counts map[string]int) []ReactionCount {
+	summary := make([]ReactionCount, 0, len(counts))
+	for _, kind := range k {
+		if count := counts[kind.Type]; count > 0 {
+			summary = append(summary, ReactionCount{Type: kind.Type, Emoji: kind.Emoji, Count: count})
+		}
+	}
+	return summary
+}
+
+func ParseReactionKinds(spec string) (ReactionKinds, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultReactionKinds, nil
+	}
+
+	var kinds ReactionKinds
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		kind, known := DefaultReactionKinds.Find(strings.ToLower(parts[0]))
+		switch {
+		case len(parts) == 1 && known:
+		case len(parts) == 3:
+			kind = ReactionKind{Type: strings.ToLower(parts[0]), Emoji: parts[1], Sentiment: Sentiment(parts[2])}
+		default:
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidReactionKinds, entry)
+		}
+
+		if kind.Type == "" || kind.Emoji == "" {
+			return nil, fmt.Errorf("%w: %q needs a type and an emoji", ErrInvalidReactionKinds, entry)
+		}
+		switch kind.Sentiment {
+		case SentimentPositive, SentimentNegative, SentimentNeutral:
+		default:
+			return nil, fmt.Errorf("%w: %q has unknown sentiment", ErrInvalidReactionKinds, entry)
+		}
+		if _, exists := kinds.Find(kind.Type); exists {
+			return nil, fmt.Errorf("%w: duplicate kind %q", ErrInvalidReactionKinds, kind.Type)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	for _, required := range []string{ReactionLike, ReactionDislike} {
+		if _, ok := kinds.Find(required); !ok {
+			return nil, fmt.Errorf("%w: %q is required", ErrInvalidReactionKinds, required)
+		}
+	}
+
+	return kinds, nil
+}
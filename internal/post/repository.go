@@ -3,23 +3,31 @@ package post
 import (
 	"context"
 	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
+	"time"
 )
 
 type PostRepository interface {
 	Create(ctx context.Context, post *Post) error
 	FindByID(ctx context.Context, id int64) (*Post, error)
-	FindAll(ctx context.Context) ([]*Post, error)
-	Update(ctx context.Context, post *Post) error
+	FindVisibleByID(ctx context.Context, id, viewerID int64) (*Post, error)
+	FindAll(ctx context.Context, viewerID int64) ([]*Post, error)
+	Update(ctx context.Context, post *Post, prior revision.Revision) error
+	FindRevisions(ctx context.Context, postID int64) ([]revision.Revision, error)
+	SetCommentsLocked(ctx context.Context, postID int64, locked bool) error
+	SetVisibility(ctx context.Context, postID int64, visibility Visibility) error
+	SetPinnedComment(ctx context.Context, postID int64, commentID *int64) error
 	Delete(ctx context.Context, id int64) error
-	FindByAuthor(ctx context.Context, author int64) ([]*Post, error)
-	FindByHashtag(ctx context.Context, hashtag *hashtag.Hashtag) ([]*Post, error)
-	IncrementLikes(ctx context.Context, post int64) error
-	DecrementLikes(ctx context.Context, post int64) error
-	IncrementDislikes(ctx context.Context, post int64) error
-	DecrementDislikes(ctx context.Context, post int64) error
-	FindWithPagination(ctx context.Context, limit, offset int) ([]*Post, error)
-	HasUserReacted(ctx context.Context, userID, postID int64) (bool, string, error)
-	AddReaction(ctx context.Context, userID, postID int64, reactionType string) error
-	UpdateReaction(ctx context.Context, userID, postID int64, oldType, newType string) error
+	FindByAuthor(ctx context.Context, author, viewerID int64, cursor *pagination.Cursor, limit int) ([]*Post, error)
+	FindByHashtag(ctx context.Context, hashtag *hashtag.Hashtag, viewerID int64, cursor *pagination.Cursor, limit int) ([]*Post, error)
+	FindWithPagination(ctx context.Context, viewerID int64, cursor *pagination.Cursor, limit int) ([]*Post, error)
+	FindFeed(ctx context.Context, viewerID int64, sort string, asOf time.Time, cursor *pagination.Cursor, limit int) ([]*Post, error)
+	FindTimeline(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]TimelineItem, error)
+	SetReaction(ctx context.Context, userID, postID int64, reactionType string, kinds ReactionKinds) (*ReactionState, error)
+	RemoveReaction(ctx context.Context, userID, postID int64, kinds ReactionKinds) (*ReactionState, error)
+	GetReactionCounts(ctx context.Context, postIDs []int64) (map[int64]map[string]int, error)
+	FindReactors(ctx context.Context, postID, viewerID int64, reactionType string, cursor *pagination.Cursor, limit int) ([]Reactor, error)
+	FindLikedPosts(ctx context.Context, userID, viewerID int64, cursor *pagination.Cursor, limit int) ([]LikedPost, error)
 	GetUserReactions(ctx context.Context, userID int64, postIDs []int64) (map[int64]string, error)
 }
@@ -0,0 +1,19 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"socialmediafeed/pkg/policy"
+)
+
+var ErrAccountRestricted = errors.New("your account is restricted from posting or reacting")
+
+type SanctionPolicy = policy.Sanctions
+
+func (s *Service) SetSanctionPolicy(sanctions SanctionPolicy) {
+	s.sanctions = sanctions
+}
+
+func (s *Service) checkCanWrite(ctx context.Context, userID int64) error {
+	return policy.CheckCanWrite(ctx, s.sanctions, userID, ErrAccountRestricted)
+}
@@ -4,20 +4,85 @@ import (
 	"context"
 	"fmt"
 	"socialmediafeed/internal/hashtag"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/revision"
 	"time"
 )
 
+type HashtagPolicy interface {
+	ResolveTags(ctx context.Context, tags []string) (*hashtag.Resolution, error)
+	FlagPost(ctx context.Context, postID int64, tags []string) error
+}
+
+type PrivacyPolicy interface {
+	LikesVisible(ctx context.Context, ownerID, viewerID int64) (bool, error)
+	BlocksMention(ctx context.Context, authorID int64, usernames []string) (bool, error)
+}
+
 type Service struct {
-	repo PostRepository
+	repo        PostRepository
+	hashtags    HashtagPolicy
+	privacy     PrivacyPolicy
+	muted       MutedWordPolicy
+	sanctions   SanctionPolicy
+	permissions PermissionPolicy
+	kinds       ReactionKinds
+	windows     revision.EditWindows
 }
 
 func NewService(repo PostRepository) *Service {
 	return &Service{
-		repo: repo,
+		repo:  repo,
+		kinds: DefaultReactionKinds,
 	}
 }
 
-func (s *Service) CreatePost(ctx context.Context, authorID int64, content, imageURL string) (*Post, error) {
+func (s *Service) SetPrivacyPolicy(policy PrivacyPolicy) {
+	s.privacy = policy
+}
+
+func (s *Service) SetEditWindows(windows revision.EditWindows) {
+	s.windows = windows
+}
+
+func (s *Service) SetReactionKinds(kinds ReactionKinds) {
+	s.kinds = kinds
+}
+
+func (s *Service) ReactionKinds() ReactionKinds {
+	return s.kinds
+}
+
+func (s *Service) SetHashtagPolicy(policy HashtagPolicy) {
+	s.hashtags = policy
+}
+
+func (s *Service) applyHashtagPolicy(ctx context.Context, post *Post) ([]string, error) {
+	if s.hashtags == nil || len(post.Hashtags) == 0 {
+		return nil, nil
+	}
+
+	resolution, err := s.hashtags.ResolveTags(ctx, post.Hashtags)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Hashtags = resolution.Tags
+	return resolution.Flagged, nil
+}
+
+func (s *Service) flagPost(ctx context.Context, postID int64, flagged []string) error {
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	if err := s.hashtags.FlagPost(ctx, postID, flagged); err != nil {
+		return fmt.Errorf("failed to flag post: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) CreatePost(ctx context.Context, authorID int64, content, imageURL, visibility string) (*Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
@@ -25,37 +90,81 @@ func (s *Service) CreatePost(ctx context.Context, authorID int64, content, image
 		return nil, fmt.Errorf("post content cannot be empty")
 	}
 
+	if err := s.checkCanWrite(ctx, authorID); err != nil {
+		return nil, err
+	}
+
+	level, err := ParseVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+
 	post := NewPost(authorID, content, imageURL)
+	post.Visibility = level
 
 	if err := post.IsValid(); err != nil {
 		return nil, err
 	}
 
-	err := s.repo.Create(ctx, post)
+	if err := s.checkMentions(ctx, authorID, post.Mentions); err != nil {
+		return nil, err
+	}
+
+	flagged, err := s.applyHashtagPolicy(ctx, post)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, post); err != nil {
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
+	if err := s.flagPost(ctx, post.ID, flagged); err != nil {
+		return nil, err
+	}
+
 	return post, nil
 }
 
-func (s *Service) GetPostByID(ctx context.Context, id int64) (*Post, error) {
+func (s *Service) checkMentions(ctx context.Context, authorID int64, mentions []string) error {
+	if s.privacy == nil || len(mentions) == 0 {
+		return nil
+	}
+
+	blocked, err := s.privacy.BlocksMention(ctx, authorID, mentions)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrMentionBlocked
+	}
+	return nil
+}
+
+func (s *Service) GetPostByID(ctx context.Context, id, viewerID int64) (*Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	post, err := s.repo.FindByID(ctx, id)
+	return s.findVisiblePost(ctx, id, viewerID)
+}
+
+func (s *Service) findVisiblePost(ctx context.Context, id, viewerID int64) (*Post, error) {
+	post, err := s.repo.FindVisibleByID(ctx, id, viewerID)
+	if err != nil || post != nil {
+		return post, err
+	}
+
+	hidden, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	if post == nil {
-		return nil, fmt.Errorf("post not found")
+	if hidden == nil {
+		return nil, ErrPostNotFound
 	}
-
-	return post, nil
+	return nil, ErrPostNotVisible
 }
 
-func (s *Service) UpdatePost(ctx context.Context, id, userID int64, content, imageURL string, userRole string) (*Post, error) {
+func (s *Service) UpdatePost(ctx context.Context, id, userID int64, content, imageURL, userRole, reason string) (*Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -67,28 +176,182 @@ func (s *Service) UpdatePost(ctx context.Context, id, userID int64, content, ima
 		return nil, fmt.Errorf("post not found")
 	}
 
-	if !post.CanBeEditedBy(userID, userRole) {
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !post.CanBeEditedBy(userID, permissions) {
 		return nil, fmt.Errorf("unauthorized to edit this post")
 	}
 
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !s.windows.Allows(userRole, post.CreatedAt, now) {
+		return nil, revision.ErrEditWindowClosed
+	}
+
+	if (content == "" || content == post.Content) && (imageURL == "" || imageURL == post.MediaURL) {
+		return post, nil
+	}
+
+	prior := revision.Revision{
+		Content:  post.Content,
+		MediaURL: post.MediaURL,
+		EditorID: userID,
+		Reason:   reason,
+		EditedAt: now,
+	}
+
+	var flagged []string
 	if content != "" {
 		post.Content = content
+		post.Hashtags = hashtag.ExtractTags(content)
+		post.Mentions = ExtractMentions(content)
+
+		if err := s.checkMentions(ctx, post.AuthorID, post.Mentions); err != nil {
+			return nil, err
+		}
+
+		flagged, err = s.applyHashtagPolicy(ctx, post)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if imageURL != "" {
 		post.MediaURL = imageURL
 	}
+	if post.Mentions == nil {
+		post.Mentions = ExtractMentions(post.Content)
+	}
+
+	post.UpdatedAt = now
+	post.EditedAt = &now
+
+	err = s.repo.Update(ctx, post, prior)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.flagPost(ctx, post.ID, flagged); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func (s *Service) SetCommentsLocked(ctx context.Context, postID, userID int64, locked bool) (*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	post, err := s.findThreadPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetCommentsLocked(ctx, postID, locked); err != nil {
+		return nil, err
+	}
+
+	post.CommentsLocked = locked
+	return post, nil
+}
+
+func (s *Service) SetVisibility(ctx context.Context, postID, userID int64, visibility string) (*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	level, err := ParseVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.repo.FindByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !post.CanBeEditedBy(userID, permissions) {
+		return nil, ErrVisibilityForbidden
+	}
+
+	if err := s.repo.SetVisibility(ctx, postID, level); err != nil {
+		return nil, err
+	}
 
-	post.UpdatedAt = time.Now()
+	post.Visibility = level
+	return post, nil
+}
+
+func (s *Service) SetPinnedComment(ctx context.Context, postID, userID int64, commentID *int64) (*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
 
-	err = s.repo.Update(ctx, post)
+	post, err := s.findThreadPost(ctx, postID, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.repo.SetPinnedComment(ctx, postID, commentID); err != nil {
+		return nil, err
+	}
+
+	post.PinnedCommentID = commentID
 	return post, nil
 }
 
-func (s *Service) DeletePost(ctx context.Context, id, userID int64, userRole string) error {
+func (s *Service) findThreadPost(ctx context.Context, postID, userID int64) (*Post, error) {
+	post, err := s.repo.FindByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !post.CanBeEditedBy(userID, permissions) {
+		return nil, ErrThreadForbidden
+	}
+	return post, nil
+}
+
+func (s *Service) GetRevisions(ctx context.Context, postID, viewerID int64) (revision.History, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	post, err := s.findVisiblePost(ctx, postID, viewerID)
+	if err != nil {
+		return revision.History{}, err
+	}
+
+	revisions, err := s.repo.FindRevisions(ctx, postID)
+	if err != nil {
+		return revision.History{}, err
+	}
+
+	return revision.NewHistory(revisions, post.Content), nil
+}
+
+func (s *Service) DiffRevisions(ctx context.Context, postID, viewerID int64, from, to int) (revision.Diff, error) {
+	history, err := s.GetRevisions(ctx, postID, viewerID)
+	if err != nil {
+		return revision.Diff{}, err
+	}
+	return history.Diff(from, to)
+}
+
+func (s *Service) DeletePost(ctx context.Context, id, userID int64) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -100,126 +363,221 @@ func (s *Service) DeletePost(ctx context.Context, id, userID int64, userRole str
 		return fmt.Errorf("post not found")
 	}
 
-	if !post.CanBeDeletedBy(userID, userRole) {
+	permissions, err := s.permissionsFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !post.CanBeDeletedBy(userID, permissions) {
 		return fmt.Errorf("unauthorized to delete this post")
 	}
 
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *Service) GetAllPosts(ctx context.Context, limit, offset int) ([]*Post, error) {
+func (s *Service) GetAllPosts(ctx context.Context, viewerID int64, params pagination.Params) (pagination.Page[*Post], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindWithPagination(ctx, limit, offset)
+	posts, err := s.repo.FindWithPagination(ctx, viewerID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[*Post]{}, err
+	}
+
+	return pagination.NewPage(posts, params.Limit, postTimeCursor), nil
 }
 
-func (s *Service) GetFeed(ctx context.Context, sortBy string, limit, offset int) ([]*Post, error) {
+func (s *Service) GetFeed(ctx context.Context, viewerID int64, sortBy string, params pagination.Params) (pagination.Page[*Post], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	posts, err := s.repo.FindAll(ctx)
-	if err != nil {
-		return nil, err
+	strategy := StrategyByName(sortBy)
+	if strategy.Name() == "date" {
+		return s.mutedPostPage(ctx, viewerID, params, postTimeCursor, func(cursor *pagination.Cursor) ([]*Post, error) {
+			return s.repo.FindWithPagination(ctx, viewerID, cursor, params.FetchLimit())
+		})
 	}
 
-	sorter := NewPostSorter(nil)
-	sortedPosts := sorter.SortByName(posts, sortBy)
-
-	start := offset
-	end := offset + limit
+	asOf := time.Now()
+	if params.Cursor != nil {
+		var err error
+		if _, asOf, err = params.Cursor.Score(strategy.Name()); err != nil {
+			return pagination.Page[*Post]{}, err
+		}
+	}
 
-	if start >= len(sortedPosts) {
-		return []*Post{}, nil
+	cursorFor := func(p *Post) *pagination.Cursor {
+		return pagination.NewScoreCursor(strategy.Name(), p.Score, asOf, p.ID)
 	}
-	if end > len(sortedPosts) {
-		end = len(sortedPosts)
+	return s.mutedPostPage(ctx, viewerID, params, cursorFor, func(cursor *pagination.Cursor) ([]*Post, error) {
+		return s.repo.FindFeed(ctx, viewerID, strategy.Name(), asOf, cursor, params.FetchLimit())
+	})
+}
+
+func (s *Service) GetPostsByAuthor(ctx context.Context, authorID, viewerID int64, params pagination.Params) (pagination.Page[*Post], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	posts, err := s.repo.FindByAuthor(ctx, authorID, viewerID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[*Post]{}, err
 	}
 
-	return sortedPosts[start:end], nil
+	return pagination.NewPage(posts, params.Limit, postTimeCursor), nil
 }
 
-func (s *Service) GetPostsByAuthor(ctx context.Context, authorID int64, limit, offset int) ([]*Post, error) {
+func (s *Service) GetPostsByHashtag(ctx context.Context, hashtag *hashtag.Hashtag, viewerID int64, params pagination.Params) (pagination.Page[*Post], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindByAuthor(ctx, authorID)
+	return s.mutedPostPage(ctx, viewerID, params, postTimeCursor, func(cursor *pagination.Cursor) ([]*Post, error) {
+		return s.repo.FindByHashtag(ctx, hashtag, viewerID, cursor, params.FetchLimit())
+	})
 }
 
-func (s *Service) GetPostsByHashtag(ctx context.Context, hashtag *hashtag.Hashtag, limit, offset int) ([]*Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+func (s *Service) SetReaction(ctx context.Context, userID, postID int64, reactionType string) (*ReactionState, error) {
+	if _, ok := s.kinds.Find(reactionType); !ok {
+		return nil, ErrInvalidReaction
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	return s.repo.FindByHashtag(ctx, hashtag)
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findVisiblePost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	state, err := s.repo.SetReaction(ctx, userID, postID, reactionType, s.kinds)
+	if err != nil {
+		return nil, err
+	}
+	return state, s.attachReactionSummary(ctx, state)
 }
 
-func (s *Service) LikePost(ctx context.Context, userID, postID int64) error {
+func (s *Service) RemoveReaction(ctx context.Context, userID, postID int64) (*ReactionState, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	hasReacted, reactionType, err := s.repo.HasUserReacted(ctx, userID, postID)
+	if err := s.checkCanWrite(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findVisiblePost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	state, err := s.repo.RemoveReaction(ctx, userID, postID, s.kinds)
+	if err != nil {
+		return nil, err
+	}
+	return state, s.attachReactionSummary(ctx, state)
+}
+
+func (s *Service) attachReactionSummary(ctx context.Context, state *ReactionState) error {
+	counts, err := s.repo.GetReactionCounts(ctx, []int64{state.PostID})
 	if err != nil {
 		return err
 	}
+	state.Reactions = s.kinds.Summary(counts[state.PostID])
+	return nil
+}
 
-	if hasReacted {
-		if reactionType == "like" {
-			return fmt.Errorf("you have already liked this post")
-		}
-		if err := s.repo.UpdateReaction(ctx, userID, postID, "dislike", "like"); err != nil {
-			return err
-		}
-		if err := s.repo.DecrementDislikes(ctx, postID); err != nil {
+func (s *Service) AttachReactions(ctx context.Context, viewerID int64, posts ...*Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	postIDs := make([]int64, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	counts, err := s.repo.GetReactionCounts(ctx, postIDs)
+	if err != nil {
+		return err
+	}
+
+	viewerReactions := make(map[int64]string)
+	if viewerID != 0 {
+		viewerReactions, err = s.repo.GetUserReactions(ctx, viewerID, postIDs)
+		if err != nil {
 			return err
 		}
-		return s.repo.IncrementLikes(ctx, postID)
 	}
 
-	if err := s.repo.AddReaction(ctx, userID, postID, "like"); err != nil {
-		return err
+	for _, post := range posts {
+		post.Reactions = s.kinds.Summary(counts[post.ID])
+		post.ViewerReaction = viewerReactions[post.ID]
 	}
-	return s.repo.IncrementLikes(ctx, postID)
+
+	return nil
 }
 
-func (s *Service) DislikePost(ctx context.Context, userID, postID int64) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+func (s *Service) GetReactors(ctx context.Context, postID, viewerID int64, reactionType string, params pagination.Params) (pagination.Page[Reactor], error) {
+	if reactionType != "" {
+		if _, ok := s.kinds.Find(reactionType); !ok {
+			return pagination.Page[Reactor]{}, ErrInvalidReaction
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	hasReacted, reactionType, err := s.repo.HasUserReacted(ctx, userID, postID)
+	if _, err := s.findVisiblePost(ctx, postID, viewerID); err != nil {
+		return pagination.Page[Reactor]{}, err
+	}
+
+	reactors, err := s.repo.FindReactors(ctx, postID, viewerID, reactionType, params.Cursor, params.FetchLimit())
 	if err != nil {
-		return err
+		return pagination.Page[Reactor]{}, err
 	}
 
-	if hasReacted {
-		if reactionType == "dislike" {
-			return fmt.Errorf("you have already disliked this post")
+	return pagination.NewPage(reactors, params.Limit, func(r Reactor) *pagination.Cursor {
+		return pagination.NewTimeCursor(r.ReactedAt, r.UserID)
+	}), nil
+}
+
+func (s *Service) GetLikedPosts(ctx context.Context, ownerID, viewerID int64, params pagination.Params) (pagination.Page[LikedPost], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if ownerID != viewerID {
+		if s.privacy == nil {
+			return pagination.Page[LikedPost]{}, ErrLikesHidden
 		}
-		if err := s.repo.UpdateReaction(ctx, userID, postID, "like", "dislike"); err != nil {
-			return err
+		visible, err := s.privacy.LikesVisible(ctx, ownerID, viewerID)
+		if err != nil {
+			return pagination.Page[LikedPost]{}, err
 		}
-		if err := s.repo.DecrementLikes(ctx, postID); err != nil {
-			return err
+		if !visible {
+			return pagination.Page[LikedPost]{}, ErrLikesHidden
 		}
-		return s.repo.IncrementDislikes(ctx, postID)
 	}
 
-	if err := s.repo.AddReaction(ctx, userID, postID, "dislike"); err != nil {
-		return err
+	posts, err := s.repo.FindLikedPosts(ctx, ownerID, viewerID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[LikedPost]{}, err
 	}
-	return s.repo.IncrementDislikes(ctx, postID)
+
+	return pagination.NewPage(posts, params.Limit, func(p LikedPost) *pagination.Cursor {
+		return pagination.NewTimeCursor(p.LikedAt, p.ID)
+	}), nil
 }
 
-func (s *Service) ApplyFilters(ctx context.Context, postID int64, filters []string) (*Post, error) {
+func (s *Service) ApplyFilters(ctx context.Context, postID, viewerID int64, filters []string) (*Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	post, err := s.repo.FindByID(ctx, postID)
+	post, err := s.findVisiblePost(ctx, postID, viewerID)
 	if err != nil {
 		return nil, err
 	}
-	if post == nil {
-		return nil, fmt.Errorf("post not found")
-	}
 
 	chain := NewFilterChain()
 
@@ -268,11 +626,11 @@ func (s *Service) ApplyFilters(ctx context.Context, postID int64, filters []stri
 	return decoratedPost, nil
 }
 
-func (s *Service) GetTrendingPosts(ctx context.Context, limit int) ([]*Post, error) {
+func (s *Service) GetTrendingPosts(ctx context.Context, viewerID int64, limit int) ([]*Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	posts, err := s.repo.FindAll(ctx)
+	posts, err := s.repo.FindAll(ctx, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -286,3 +644,16 @@ func (s *Service) GetTrendingPosts(ctx context.Context, limit int) ([]*Post, err
 
 	return sorted, nil
 }
+
+func (s *Service) GetTimeline(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[TimelineItem], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.mutedTimelinePage(ctx, userID, params, func(cursor *pagination.Cursor) ([]TimelineItem, error) {
+		return s.repo.FindTimeline(ctx, userID, cursor, params.FetchLimit())
+	})
+}
+
+func postTimeCursor(p *Post) *pagination.Cursor {
+	return pagination.NewTimeCursor(p.CreatedAt, p.ID)
+}
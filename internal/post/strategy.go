@@ -7,9 +7,27 @@ import (
 
 type SortStrategy interface {
 	Sort(posts []*Post) []*Post
+	Score(post *Post, asOf time.Time) float64
 	Name() string
 }
 
+func sortByScore(posts []*Post, strategy SortStrategy) []*Post {
+	sorted := make([]*Post, len(posts))
+	copy(sorted, posts)
+
+	asOf := time.Now()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		scoreI := strategy.Score(sorted[i], asOf)
+		scoreJ := strategy.Score(sorted[j], asOf)
+		if scoreI != scoreJ {
+			return scoreI > scoreJ
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	return sorted
+}
+
 type DateStrategy struct{}
 
 func NewDateStrategy() SortStrategy {
@@ -20,13 +38,20 @@ func (s *DateStrategy) Sort(posts []*Post) []*Post {
 	sorted := make([]*Post, len(posts))
 	copy(sorted, posts)
 
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID > sorted[j].ID
 	})
 
 	return sorted
 }
 
+func (s *DateStrategy) Score(post *Post, asOf time.Time) float64 {
+	return 0
+}
+
 func (s *DateStrategy) Name() string {
 	return "date"
 }
@@ -38,14 +63,11 @@ func NewLikesStrategy() SortStrategy {
 }
 
 func (s *LikesStrategy) Sort(posts []*Post) []*Post {
-	sorted := make([]*Post, len(posts))
-	copy(sorted, posts)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Likes > sorted[j].Likes
-	})
+	return sortByScore(posts, s)
+}
 
-	return sorted
+func (s *LikesStrategy) Score(post *Post, asOf time.Time) float64 {
+	return float64(post.Likes)
 }
 
 func (s *LikesStrategy) Name() string {
@@ -59,16 +81,11 @@ func NewEngagementStrategy() SortStrategy {
 }
 
 func (s *EngagementStrategy) Sort(posts []*Post) []*Post {
-	sorted := make([]*Post, len(posts))
-	copy(sorted, posts)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		engagementI := sorted[i].Likes + sorted[i].Dislikes
-		engagementJ := sorted[j].Likes + sorted[j].Dislikes
-		return engagementI > engagementJ
-	})
+	return sortByScore(posts, s)
+}
 
-	return sorted
+func (s *EngagementStrategy) Score(post *Post, asOf time.Time) float64 {
+	return float64(post.Likes + post.Dislikes)
 }
 
 func (s *EngagementStrategy) Name() string {
@@ -82,29 +99,17 @@ func NewTrendingStrategy() SortStrategy {
 }
 
 func (s *TrendingStrategy) Sort(posts []*Post) []*Post {
-	sorted := make([]*Post, len(posts))
-	copy(sorted, posts)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		scoreI := s.calculateTrendingScore(sorted[i])
-		scoreJ := s.calculateTrendingScore(sorted[j])
-		return scoreI > scoreJ
-	})
-
-	return sorted
+	return sortByScore(posts, s)
 }
 
-func (s *TrendingStrategy) calculateTrendingScore(post *Post) float64 {
-	hoursSinceCreation := time.Since(post.CreatedAt).Hours()
-
-	if hoursSinceCreation == 0 {
-		hoursSinceCreation = 1
+func (s *TrendingStrategy) Score(post *Post, asOf time.Time) float64 {
+	hoursSinceCreation := asOf.Sub(post.CreatedAt).Hours()
+	if hoursSinceCreation < 0 {
+		hoursSinceCreation = 0
 	}
 
 	engagement := float64(post.Likes + post.Dislikes)
-	timeDecay := 1.0 / (hoursSinceCreation + 2)
-
-	return engagement * timeDecay
+	return engagement / (hoursSinceCreation + 2)
 }
 
 func (s *TrendingStrategy) Name() string {
@@ -118,19 +123,10 @@ func NewControversialStrategy() SortStrategy {
 }
 
 func (s *ControversialStrategy) Sort(posts []*Post) []*Post {
-	sorted := make([]*Post, len(posts))
-	copy(sorted, posts)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		controversyI := s.calculateControversy(sorted[i])
-		controversyJ := s.calculateControversy(sorted[j])
-		return controversyI > controversyJ
-	})
-
-	return sorted
+	return sortByScore(posts, s)
 }
 
-func (s *ControversialStrategy) calculateControversy(post *Post) float64 {
+func (s *ControversialStrategy) Score(post *Post, asOf time.Time) float64 {
 	total := post.Likes + post.Dislikes
 	if total == 0 {
 		return 0
@@ -163,14 +159,11 @@ func NewRandomStrategy() SortStrategy {
 }
 
 func (s *RandomStrategy) Sort(posts []*Post) []*Post {
-	sorted := make([]*Post, len(posts))
-	copy(sorted, posts)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return (sorted[i].ID+sorted[j].ID)%2 == 0
-	})
+	return sortByScore(posts, s)
+}
 
-	return sorted
+func (s *RandomStrategy) Score(post *Post, asOf time.Time) float64 {
+	return float64((post.ID * 2654435761) % 4294967296)
 }
 
 func (s *RandomStrategy) Name() string {
@@ -201,22 +194,23 @@ func (ps *PostSorter) Sort(posts []*Post) []*Post {
 }
 
 func (ps *PostSorter) SortByName(posts []*Post, strategyName string) []*Post {
-	switch strategyName {
-	case "date", "newest":
-		ps.SetStrategy(NewDateStrategy())
+	ps.SetStrategy(StrategyByName(strategyName))
+	return ps.Sort(posts)
+}
+
+func StrategyByName(name string) SortStrategy {
+	switch name {
 	case "likes", "popular":
-		ps.SetStrategy(NewLikesStrategy())
+		return NewLikesStrategy()
 	case "engagement":
-		ps.SetStrategy(NewEngagementStrategy())
+		return NewEngagementStrategy()
 	case "trending", "hot":
-		ps.SetStrategy(NewTrendingStrategy())
+		return NewTrendingStrategy()
 	case "controversial":
-		ps.SetStrategy(NewControversialStrategy())
+		return NewControversialStrategy()
 	case "random":
-		ps.SetStrategy(NewRandomStrategy())
+		return NewRandomStrategy()
 	default:
-		ps.SetStrategy(NewDateStrategy())
+		return NewDateStrategy()
 	}
-
-	return ps.Sort(posts)
 }
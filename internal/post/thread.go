@@ -0,0 +1,8 @@
+package post
+
+import "errors"
+
+var (
+	ErrThreadForbidden  = errors.New("only the post author or a moderator can manage its comments")
+	ErrCommentNotOnPost = errors.New("comment is not a top-level comment on this post")
+)
@@ -0,0 +1,61 @@
+package post
+
+import (
+	"errors"
+	"strings"
+)
+
+type Visibility string
+
+const (
+	VisibilityPublic    Visibility = "public"
+	VisibilityFollowers Visibility = "followers"
+	VisibilityMentioned Visibility = "mentioned"
+)
+
+var (
+	ErrInvalidVisibility   = errors.New("invalid visibility, expected public, followers or mentioned")
+	ErrVisibilityForbidden = errors.New("only the post author or a moderator can change its visibility")
+	ErrMentionBlocked      = errors.New("you cannot mention a user you have blocked or who has blocked you")
+)
+
+func ParseVisibility(value string) (Visibility, error) {
+	switch visibility := Visibility(strings.ToLower(strings.TrimSpace(value))); visibility {
+	case "":
+		return VisibilityPublic, nil
+	case VisibilityPublic, VisibilityFollowers, VisibilityMentioned:
+		return visibility, nil
+	default:
+		return "", ErrInvalidVisibility
+	}
+}
+
+func ExtractMentions(content string) []string {
+	mentions := make([]string, 0)
+	seen := make(map[string]bool)
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && isMentionChar(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isMentionChar(runes[end]) {
+			end++
+		}
+
+		username := strings.ToLower(string(runes[i+1 : end]))
+		if username != "" && !seen[username] {
+			seen[username] = true
+			mentions = append(mentions, username)
+		}
+		i = end - 1
+	}
+
+	return mentions
+}
+
+func isMentionChar(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_'
+}
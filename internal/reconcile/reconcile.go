@@ -0,0 +1,63 @@
+package reconcile
+
+import "time"
+
+type Counter string
+
+const (
+	CounterPostLikes     Counter = "post_likes"
+	CounterPostDislikes  Counter = "post_dislikes"
+	CounterReactionCount Counter = "post_reaction_count"
+	CounterHashtagUsage  Counter = "hashtag_usage"
+)
+
+const (
+	DefaultBatchSize = 500
+	MaxReported      = 100
+)
+
+type Discrepancy struct {
+	Counter  Counter `json:"counter"`
+	EntityID int64   `json:"entity_id"`
+	Key      string  `json:"key,omitempty"`
+	Stored   int     `json:"stored"`
+	Actual   int     `json:"actual"`
+}
+
+type Batch struct {
+	Discrepancies []Discrepancy
+	Checked       int
+	LastID        int64
+}
+
+type Mapping struct {
+	Positive []string
+	Negative []string
+}
+
+type Options struct {
+	DryRun    bool
+	BatchSize int
+}
+
+type Report struct {
+	DryRun          bool            `json:"dry_run"`
+	PostsChecked    int             `json:"posts_checked"`
+	HashtagsChecked int             `json:"hashtags_checked"`
+	Found           int             `json:"found"`
+	Fixed           int             `json:"fixed"`
+	ByCounter       map[Counter]int `json:"by_counter"`
+	Discrepancies   []Discrepancy   `json:"discrepancies"`
+	StartedAt       time.Time       `json:"started_at"`
+	FinishedAt      time.Time       `json:"finished_at"`
+}
+
+func (r *Report) add(batch *Batch) {
+	for _, d := range batch.Discrepancies {
+		r.Found++
+		r.ByCounter[d.Counter]++
+		if len(r.Discrepancies) < MaxReported {
+			r.Discrepancies = append(r.Discrepancies, d)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package reconcile
+
+import "context"
+
+type Repository interface {
+	FindPostDrift(ctx context.Context, mapping Mapping, afterID int64, limit int) (*Batch, error)
+	FindHashtagDrift(ctx context.Context, afterID int64, limit int) (*Batch, error)
+	Fix(ctx context.Context, mapping Mapping, drift []Discrepancy) error
+}
@@ -0,0 +1,94 @@
+package reconcile
+
+import (
+	"context"
+	"socialmediafeed/internal/post"
+	"time"
+)
+
+type Service struct {
+	repo    Repository
+	mapping Mapping
+}
+
+func NewService(repo Repository, kinds post.ReactionKinds) *Service {
+	var mapping Mapping
+	for _, kind := range kinds {
+		switch kind.Sentiment {
+		case post.SentimentPositive:
+			mapping.Positive = append(mapping.Positive, kind.Type)
+		case post.SentimentNegative:
+			mapping.Negative = append(mapping.Negative, kind.Type)
+		}
+	}
+
+	return &Service{
+		repo:    repo,
+		mapping: mapping,
+	}
+}
+
+func (s *Service) Reconcile(ctx context.Context, opts Options) (*Report, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	report := &Report{
+		DryRun:        opts.DryRun,
+		ByCounter:     make(map[Counter]int),
+		Discrepancies: []Discrepancy{},
+		StartedAt:     time.Now(),
+	}
+
+	scans := []struct {
+		checked *int
+		find    func(ctx context.Context, afterID int64, limit int) (*Batch, error)
+	}{
+		{&report.PostsChecked, func(ctx context.Context, afterID int64, limit int) (*Batch, error) {
+			return s.repo.FindPostDrift(ctx, s.mapping, afterID, limit)
+		}},
+		{&report.HashtagsChecked, s.repo.FindHashtagDrift},
+	}
+
+	for _, scan := range scans {
+		var afterID int64
+		for {
+			batch, err := s.runBatch(ctx, scan.find, afterID, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			*scan.checked += batch.Checked
+			report.add(batch)
+			if !opts.DryRun {
+				report.Fixed += len(batch.Discrepancies)
+			}
+
+			if batch.Checked < opts.BatchSize {
+				break
+			}
+			afterID = batch.LastID
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (s *Service) runBatch(ctx context.Context, find func(context.Context, int64, int) (*Batch, error), afterID int64, opts Options) (*Batch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	batch, err := find(ctx, afterID, opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun && len(batch.Discrepancies) > 0 {
+		if err := s.repo.Fix(ctx, s.mapping, batch.Discrepancies); err != nil {
+			return nil, err
+		}
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,256 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/permission"
+	"socialmediafeed/pkg/responce"
+	"strconv"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) RegisterAuthenticatedRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("POST /api/reports", auth(h.SubmitReport))
+	mux.HandleFunc("GET /api/admin/reports", auth(h.GetQueue))
+	mux.HandleFunc("GET /api/admin/reports/{id}", auth(h.GetCase))
+	mux.HandleFunc("POST /api/admin/reports/{id}/resolve", auth(h.ResolveCase))
+	mux.HandleFunc("GET /api/admin/moderation/actions", auth(h.GetActionLog))
+	mux.HandleFunc("POST /api/admin/moderation/actions", auth(h.TakeAction))
+}
+
+func (h *Handler) SubmitReport(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		TargetType string `json:"target_type"`
+		TargetID   int64  `json:"target_id"`
+		Reason     string `json:"reason"`
+		Details    string `json:"details"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	targetType, err := ParseTargetType(req.TargetType)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	reason, err := ParseReason(req.Reason)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	report, err := h.service.SubmitReport(r.Context(), userID, targetType, req.TargetID, reason, req.Details)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.Created(w, report)
+}
+
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	status, err := ParseStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	var targetType TargetType
+	if value := r.URL.Query().Get("target_type"); value != "" {
+		if targetType, err = ParseTargetType(value); err != nil {
+			response.BadRequest(w, err.Error())
+			return
+		}
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetQueue(r.Context(), status, targetType, params)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) GetCase(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid report case ID")
+		return
+	}
+
+	c, err := h.service.GetCase(r.Context(), id)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, c)
+}
+
+func (h *Handler) ResolveCase(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid report case ID")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	action, err := ParseAction(req.Action)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	c, err := h.service.ResolveCase(r.Context(), id, getUserIDFromContext(r.Context()), action, req.Note)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, c)
+}
+
+func (h *Handler) GetActionLog(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetActionLog(r.Context(), params)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) TakeAction(w http.ResponseWriter, r *http.Request) {
+	if !requireModerator(w, r) {
+		return
+	}
+
+	var req struct {
+		TargetType string `json:"target_type"`
+		TargetID   int64  `json:"target_id"`
+		Action     string `json:"action"`
+		Note       string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	targetType, err := ParseTargetType(req.TargetType)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	action, err := ParseAction(req.Action)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	entry, err := h.service.TakeAction(r.Context(), getUserIDFromContext(r.Context()), targetType, req.TargetID, action, req.Note)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	response.Created(w, entry)
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTargetNotFound), errors.Is(err, ErrCaseNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrAlreadyReported), errors.Is(err, ErrAlreadyResolved), errors.Is(err, ErrCaseClaimed):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrCannotBanUser):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrCannotReportSelf), errors.Is(err, ErrDetailsRequired), errors.Is(err, ErrDetailsTooLong),
+		errors.Is(err, ErrInvalidAction), errors.Is(err, ErrCannotRemoveUser), errors.Is(err, pagination.ErrInvalidCursor):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func requireModerator(w http.ResponseWriter, r *http.Request) bool {
+	if getUserIDFromContext(r.Context()) == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return false
+	}
+
+	if !permission.Has(permission.FromContext(r.Context()), permission.ReportReview) {
+		response.Forbidden(w, "Permission "+string(permission.ReportReview)+" required")
+		return false
+	}
+
+	return true
+}
+
+func getUserIDFromContext(ctx context.Context) int64 {
+	if userID, ok := ctx.Value("userID").(int64); ok {
+		return userID
+	}
+	return 0
+}
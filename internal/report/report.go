@@ -0,0 +1,188 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"socialmediafeed/pkg/pagination"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TargetType string
+
+const (
+	TargetPost    TargetType = "post"
+	TargetComment TargetType = "comment"
+	TargetUser    TargetType = "user"
+)
+
+type Reason string
+
+const (
+	ReasonSpam           Reason = "spam"
+	ReasonHarassment     Reason = "harassment"
+	ReasonHateSpeech     Reason = "hate_speech"
+	ReasonViolence       Reason = "violence"
+	ReasonSexualContent  Reason = "sexual_content"
+	ReasonMisinformation Reason = "misinformation"
+	ReasonSelfHarm       Reason = "self_harm"
+	ReasonOther          Reason = "other"
+)
+
+type Status string
+
+const (
+	StatusOpen     Status = "open"
+	StatusResolved Status = "resolved"
+)
+
+type Action string
+
+const (
+	ActionDismiss Action = "dismiss"
+	ActionRemove  Action = "remove"
+	ActionWarn    Action = "warn"
+	ActionBan     Action = "ban"
+)
+
+const MaxDetailsLength = 1000
+
+var (
+	ErrInvalidTarget    = errors.New("invalid target type, expected post, comment or user")
+	ErrInvalidReason    = errors.New("invalid reason, expected spam, harassment, hate_speech, violence, sexual_content, misinformation, self_harm or other")
+	ErrInvalidStatus    = errors.New("invalid status, expected open or resolved")
+	ErrInvalidAction    = errors.New("invalid action, expected dismiss, remove, warn or ban")
+	ErrDetailsRequired  = errors.New("details are required when the reason is other")
+	ErrDetailsTooLong   = errors.New("details exceed maximum length of 1000 characters")
+	ErrTargetNotFound   = errors.New("reported content not found")
+	ErrCannotReportSelf = errors.New("you cannot report your own content")
+	ErrAlreadyReported  = errors.New("you have already reported this")
+	ErrCaseNotFound     = errors.New("report case not found")
+	ErrAlreadyResolved  = errors.New("report case is already resolved")
+	ErrCaseClaimed      = errors.New("report case is being resolved by another moderator")
+	ErrCannotRemoveUser = errors.New("remove applies to posts and comments, use ban for users")
+	ErrCannotBanUser    = errors.New("you cannot ban the owner of this content")
+)
+
+type Report struct {
+	ID         int64      `json:"id"`
+	CaseID     int64      `json:"case_id"`
+	ReporterID int64      `json:"reporter_id"`
+	TargetType TargetType `json:"target_type"`
+	TargetID   int64      `json:"target_id"`
+	Reason     Reason     `json:"reason"`
+	Details    string     `json:"details,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type Case struct {
+	ID              int64          `json:"id"`
+	TargetType      TargetType     `json:"target_type"`
+	TargetID        int64          `json:"target_id"`
+	TargetOwnerID   int64          `json:"target_owner_id"`
+	Status          Status         `json:"status"`
+	ReportCount     int            `json:"report_count"`
+	Reasons         map[Reason]int `json:"reasons"`
+	FirstReportedAt time.Time      `json:"first_reported_at"`
+	LastReportedAt  time.Time      `json:"last_reported_at"`
+	Resolution      Action         `json:"resolution,omitempty"`
+	ResolvedBy      *int64         `json:"resolved_by,omitempty"`
+	ResolutionNote  string         `json:"resolution_note,omitempty"`
+	ResolvedAt      *time.Time     `json:"resolved_at,omitempty"`
+	Reports         []Report       `json:"reports,omitempty"`
+}
+
+type LogEntry struct {
+	ID          int64      `json:"id"`
+	ModeratorID int64      `json:"moderator_id"`
+	Moderator   string     `json:"moderator"`
+	Action      Action     `json:"action"`
+	TargetType  TargetType `json:"target_type"`
+	TargetID    int64      `json:"target_id"`
+	CaseID      *int64     `json:"case_id,omitempty"`
+	Note        string     `json:"note,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func ParseTargetType(value string) (TargetType, error) {
+	switch target := TargetType(value); target {
+	case TargetPost, TargetComment, TargetUser:
+		return target, nil
+	default:
+		return "", ErrInvalidTarget
+	}
+}
+
+func ParseReason(value string) (Reason, error) {
+	switch reason := Reason(value); reason {
+	case ReasonSpam, ReasonHarassment, ReasonHateSpeech, ReasonViolence,
+		ReasonSexualContent, ReasonMisinformation, ReasonSelfHarm, ReasonOther:
+		return reason, nil
+	default:
+		return "", ErrInvalidReason
+	}
+}
+
+func ParseStatus(value string) (Status, error) {
+	switch status := Status(value); status {
+	case "":
+		return StatusOpen, nil
+	case StatusOpen, StatusResolved:
+		return status, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+func ParseAction(value string) (Action, error) {
+	switch action := Action(value); action {
+	case ActionDismiss, ActionRemove, ActionWarn, ActionBan:
+		return action, nil
+	default:
+		return "", ErrInvalidAction
+	}
+}
+
+func NewReport(reporterID int64, targetType TargetType, targetID int64, reason Reason, details string) (*Report, error) {
+	details = strings.TrimSpace(details)
+	if reason == ReasonOther && details == "" {
+		return nil, ErrDetailsRequired
+	}
+	if len(details) > MaxDetailsLength {
+		return nil, ErrDetailsTooLong
+	}
+
+	return &Report{
+		ReporterID: reporterID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Details:    details,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func (c *Case) IsOpen() bool {
+	return c.Status == StatusOpen
+}
+
+func QueueCursor(c Case) *pagination.Cursor {
+	return pagination.NewCursor(fmt.Sprintf("%d|%s", c.ReportCount, c.FirstReportedAt.Format(time.RFC3339Nano)), c.ID)
+}
+
+func ParseQueueCursor(cursor *pagination.Cursor) (int, time.Time, error) {
+	countStr, timeStr, ok := strings.Cut(cursor.Key, "|")
+	if !ok {
+		return 0, time.Time{}, pagination.ErrInvalidCursor
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return 0, time.Time{}, pagination.ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, timeStr)
+	if err != nil {
+		return 0, time.Time{}, pagination.ErrInvalidCursor
+	}
+	return count, t, nil
+}
@@ -0,0 +1,20 @@
+package report
+
+import (
+	"context"
+	"socialmediafeed/pkg/pagination"
+	"time"
+)
+
+type Repository interface {
+	FindTargetOwner(ctx context.Context, targetType TargetType, targetID int64) (int64, bool, error)
+	Create(ctx context.Context, report *Report, ownerID int64) error
+	FindQueue(ctx context.Context, status Status, targetType TargetType, cursor *pagination.Cursor, limit int) ([]Case, error)
+	FindCase(ctx context.Context, id int64) (*Case, error)
+	FindReportsByCase(ctx context.Context, caseID int64) ([]Report, error)
+	ClaimCase(ctx context.Context, caseID, moderatorID int64, now, staleBefore time.Time) (bool, error)
+	ReleaseCase(ctx context.Context, caseID, moderatorID int64) error
+	Resolve(ctx context.Context, c *Case, entry *LogEntry) (bool, error)
+	LogAction(ctx context.Context, entry *LogEntry) error
+	FindActionLog(ctx context.Context, cursor *pagination.Cursor, limit int) ([]LogEntry, error)
+}
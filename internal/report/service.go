@@ -0,0 +1,282 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"socialmediafeed/internal/user"
+	"socialmediafeed/pkg/logger"
+	"socialmediafeed/pkg/pagination"
+	"time"
+)
+
+type PostRemover interface {
+	DeletePost(ctx context.Context, id, userID int64) error
+}
+
+type CommentRemover interface {
+	DeleteComment(ctx context.Context, id, userID int64) error
+}
+
+type UserBanner interface {
+	BanUser(ctx context.Context, moderatorID, userID int64, reason string) error
+}
+
+type Notifier interface {
+	NotifyReportResolved(ctx context.Context, reporterID, reportID int64, action string) error
+	NotifyModerationWarning(ctx context.Context, userID, targetID int64, targetType, note string) error
+}
+
+const caseClaimTimeout = time.Minute
+
+type Service struct {
+	repo     Repository
+	posts    PostRemover
+	comments CommentRemover
+	users    UserBanner
+	notifier Notifier
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func (s *Service) SetEnforcement(posts PostRemover, comments CommentRemover, users UserBanner) {
+	s.posts = posts
+	s.comments = comments
+	s.users = users
+}
+
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+func (s *Service) SubmitReport(ctx context.Context, reporterID int64, targetType TargetType, targetID int64, reason Reason, details string) (*Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	report, err := NewReport(reporterID, targetType, targetID, reason, details)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, found, err := s.repo.FindTargetOwner(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTargetNotFound
+	}
+	if ownerID == reporterID {
+		return nil, ErrCannotReportSelf
+	}
+
+	if err := s.repo.Create(ctx, report, ownerID); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *Service) GetQueue(ctx context.Context, status Status, targetType TargetType, params pagination.Params) (pagination.Page[Case], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cases, err := s.repo.FindQueue(ctx, status, targetType, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Case]{}, err
+	}
+
+	return pagination.NewPage(cases, params.Limit, QueueCursor), nil
+}
+
+func (s *Service) GetCase(ctx context.Context, id int64) (*Case, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	c, err := s.repo.FindCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	c.Reports, err = s.repo.FindReportsByCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Service) ResolveCase(ctx context.Context, id, moderatorID int64, action Action, note string) (*Case, error) {
+	c, err := s.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsOpen() {
+		return nil, ErrAlreadyResolved
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	claimed, err := s.repo.ClaimCase(ctx, c.ID, moderatorID, now, now.Add(-caseClaimTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrCaseClaimed
+	}
+
+	if err := s.enforce(ctx, c.TargetType, c.TargetID, c.TargetOwnerID, moderatorID, action, note); err != nil {
+		if releaseErr := s.repo.ReleaseCase(context.WithoutCancel(ctx), c.ID, moderatorID); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+
+	c.Status = StatusResolved
+	c.Resolution = action
+	c.ResolvedBy = &moderatorID
+	c.ResolutionNote = note
+	c.ResolvedAt = &now
+
+	resolved, err := s.repo.Resolve(ctx, c, &LogEntry{
+		ModeratorID: moderatorID,
+		Action:      action,
+		TargetType:  c.TargetType,
+		TargetID:    c.TargetID,
+		CaseID:      &c.ID,
+		Note:        note,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrAlreadyResolved
+	}
+
+	for _, r := range c.Reports {
+		s.notify(func(n Notifier) error {
+			return n.NotifyReportResolved(ctx, r.ReporterID, r.ID, string(action))
+		})
+	}
+
+	return c, nil
+}
+
+func (s *Service) TakeAction(ctx context.Context, moderatorID int64, targetType TargetType, targetID int64, action Action, note string) (*LogEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if action == ActionDismiss {
+		return nil, ErrInvalidAction
+	}
+
+	ownerID, found, err := s.repo.FindTargetOwner(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTargetNotFound
+	}
+
+	if err := s.enforce(ctx, targetType, targetID, ownerID, moderatorID, action, note); err != nil {
+		return nil, err
+	}
+
+	entry := &LogEntry{
+		ModeratorID: moderatorID,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.LogAction(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *Service) GetActionLog(ctx context.Context, params pagination.Params) (pagination.Page[LogEntry], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entries, err := s.repo.FindActionLog(ctx, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[LogEntry]{}, err
+	}
+
+	return pagination.NewPage(entries, params.Limit, func(e LogEntry) *pagination.Cursor {
+		return pagination.NewTimeCursor(e.CreatedAt, e.ID)
+	}), nil
+}
+
+func (s *Service) enforce(ctx context.Context, targetType TargetType, targetID, ownerID, moderatorID int64, action Action, note string) error {
+	switch action {
+	case ActionDismiss:
+		return nil
+	case ActionRemove:
+		return s.removeTarget(ctx, targetType, targetID, moderatorID)
+	case ActionWarn:
+		s.notify(func(n Notifier) error {
+			return n.NotifyModerationWarning(ctx, ownerID, targetID, string(targetType), note)
+		})
+		return nil
+	case ActionBan:
+		if s.users == nil {
+			return fmt.Errorf("user enforcement is not configured")
+		}
+		return s.banOwner(ctx, targetType, targetID, ownerID, moderatorID, note)
+	default:
+		return ErrInvalidAction
+	}
+}
+
+func (s *Service) banOwner(ctx context.Context, targetType TargetType, targetID, ownerID, moderatorID int64, note string) error {
+	reason := note
+	if reason == "" {
+		reason = fmt.Sprintf("Moderation action on %s #%d", targetType, targetID)
+	}
+
+	err := s.users.BanUser(ctx, moderatorID, ownerID, reason)
+	if errors.Is(err, user.ErrCannotSanctionStaff) || errors.Is(err, user.ErrCannotSanctionSelf) {
+		return ErrCannotBanUser
+	}
+	return err
+}
+
+func (s *Service) removeTarget(ctx context.Context, targetType TargetType, targetID, moderatorID int64) error {
+	if targetType == TargetUser {
+		return ErrCannotRemoveUser
+	}
+
+	_, found, err := s.repo.FindTargetOwner(ctx, targetType, targetID)
+	if err != nil || !found {
+		return err
+	}
+
+	switch {
+	case targetType == TargetPost && s.posts != nil:
+		return s.posts.DeletePost(ctx, targetID, moderatorID)
+	case targetType == TargetComment && s.comments != nil:
+		return s.comments.DeleteComment(ctx, targetID, moderatorID)
+	default:
+		return fmt.Errorf("%s enforcement is not configured", targetType)
+	}
+}
+
+func (s *Service) notify(send func(Notifier) error) {
+	if s.notifier == nil {
+		return
+	}
+	if err := send(s.notifier); err != nil {
+		logger.Warning("Failed to send moderation notification: %v", err)
+	}
+}
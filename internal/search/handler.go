@@ -0,0 +1,91 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"socialmediafeed/pkg/pagination"
+	response "socialmediafeed/pkg/responce"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) RegisterRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/search", auth(h.Search))
+}
+
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	query, err := QueryFromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	query.ViewerID = getUserIDFromContext(r.Context())
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.Search(r.Context(), query, params)
+	if err != nil {
+		if isClientError(err) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func QueryFromRequest(r *http.Request) (Query, error) {
+	values := r.URL.Query()
+
+	searchType, err := ParseType(values.Get("type"))
+	if err != nil {
+		return Query{}, err
+	}
+
+	from, err := ParseDate(values.Get("from"), false)
+	if err != nil {
+		return Query{}, err
+	}
+	to, err := ParseDate(values.Get("to"), true)
+	if err != nil {
+		return Query{}, err
+	}
+
+	return Query{
+		Text:    values.Get("q"),
+		Type:    searchType,
+		Author:  values.Get("author"),
+		Hashtag: values.Get("hashtag"),
+		From:    from,
+		To:      to,
+	}, nil
+}
+
+func getUserIDFromContext(ctx context.Context) int64 {
+	if userID, ok := ctx.Value("userID").(int64); ok {
+		return userID
+	}
+	return 0
+}
+
+func isClientError(err error) bool {
+	return errors.Is(err, ErrEmptyQuery) ||
+		errors.Is(err, ErrQueryTooLong) ||
+		errors.Is(err, ErrInvalidType) ||
+		errors.Is(err, pagination.ErrInvalidCursor)
+}
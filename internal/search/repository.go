@@ -0,0 +1,10 @@
+package search
+
+import "context"
+
+type Repository interface {
+	SearchPosts(ctx context.Context, query Query) ([]Result, error)
+	SearchComments(ctx context.Context, query Query) ([]Result, error)
+	SearchUsers(ctx context.Context, query Query) ([]Result, error)
+	SearchHashtags(ctx context.Context, query Query) ([]Result, error)
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Type string
+
+const (
+	TypePosts    Type = "posts"
+	TypeUsers    Type = "users"
+	TypeHashtags Type = "hashtags"
+	TypeComments Type = "comments"
+)
+
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+	MaxQueryLength = 200
+)
+
+var (
+	ErrEmptyQuery   = errors.New("search query cannot be empty")
+	ErrQueryTooLong = errors.New("search query is too long")
+	ErrInvalidType  = errors.New("invalid search type")
+	ErrInvalidDate  = errors.New("invalid date, expected YYYY-MM-DD or RFC3339")
+)
+
+type Query struct {
+	Text    string
+	Match   string
+	Type    Type
+	Author  string
+	Hashtag string
+	From    time.Time
+	To      time.Time
+	Limit   int
+	Offset  int
+
+	ViewerID int64
+}
+
+type Result struct {
+	Type      Type      `json:"type"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	Snippet   string    `json:"snippet"`
+	Score     float64   `json:"score"`
+	AuthorID  int64     `json:"author_id,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	PostID    int64     `json:"post_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ParseType(value string) (Type, error) {
+	switch Type(value) {
+	case "":
+		return TypePosts, nil
+	case TypePosts, TypeUsers, TypeHashtags, TypeComments:
+		return Type(value), nil
+	default:
+		return "", ErrInvalidType
+	}
+}
+
+func ParseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func BuildMatchExpression(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmptyQuery
+	}
+	if len(text) > MaxQueryLength {
+		return "", ErrQueryTooLong
+	}
+
+	var terms []string
+	for i, part := range strings.Split(text, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.Join(tokenize(part), " "); phrase != "" {
+				terms = append(terms, `"`+phrase+`"`)
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(part) {
+			tokens := tokenize(field)
+			if len(tokens) == 0 {
+				continue
+			}
+
+			term := `"` + strings.Join(tokens, " ") + `"`
+			if strings.HasSuffix(field, "*") {
+				term += "*"
+			}
+			terms = append(terms, term)
+		}
+	}
+
+	if len(terms) == 0 {
+		return "", ErrEmptyQuery
+	}
+	return strings.Join(terms, " "), nil
+}
+
+func Highlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, HighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, HighlightEnd, "</mark>")
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"context"
+	"socialmediafeed/pkg/pagination"
+	"time"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func (s *Service) Search(ctx context.Context, query Query, params pagination.Params) (pagination.Page[Result], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	match, err := BuildMatchExpression(query.Text)
+	if err != nil {
+		return pagination.Page[Result]{}, err
+	}
+	query.Match = match
+
+	if query.Type == "" {
+		query.Type = TypePosts
+	}
+
+	query.Offset = 0
+	if params.Cursor != nil {
+		if params.Cursor.Key != string(query.Type) || params.Cursor.ID < 0 {
+			return pagination.Page[Result]{}, pagination.ErrInvalidCursor
+		}
+		query.Offset = int(params.Cursor.ID)
+	}
+	query.Limit = params.FetchLimit()
+
+	var results []Result
+	switch query.Type {
+	case TypePosts:
+		results, err = s.repo.SearchPosts(ctx, query)
+	case TypeComments:
+		results, err = s.repo.SearchComments(ctx, query)
+	case TypeUsers:
+		results, err = s.repo.SearchUsers(ctx, query)
+	case TypeHashtags:
+		results, err = s.repo.SearchHashtags(ctx, query)
+	default:
+		return pagination.Page[Result]{}, ErrInvalidType
+	}
+	if err != nil {
+		return pagination.Page[Result]{}, err
+	}
+
+	for i := range results {
+		results[i].Snippet = Highlight(results[i].Snippet)
+	}
+
+	page := pagination.Page[Result]{Items: results}
+	if page.Items == nil {
+		page.Items = []Result{}
+	}
+	if len(results) > params.Limit {
+		page.Items = results[:params.Limit]
+		page.NextCursor = pagination.NewCursor(string(query.Type), int64(query.Offset+params.Limit)).Encode()
+	}
+
+	return page, nil
+}
@@ -0,0 +1,17 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("you cannot block or mute yourself")
+	ErrBlocked         = errors.New("you cannot interact with this user")
+)
+
+type RelatedUser struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+type fakeRepository struct {
+	Repository
+	users           map[int64]*User
+	rolePermissions map[Role][]string
+	overrides       map[int64][]PermissionOverride
+	sanctions       []Sanction
+	expiredFor      []int64
+}
+
+func newFakeRepository(users ...*User) *fakeRepository {
+	repo := &fakeRepository{
+		users:           make(map[int64]*User),
+		rolePermissions: make(map[Role][]string),
+		overrides:       make(map[int64][]PermissionOverride),
+	}
+	for _, u := range users {
+		repo.users[u.ID] = u
+	}
+	return repo
+}
+
+func (r *fakeRepository) FindByID(ctx context.Context, id int64) (*User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (r *fakeRepository) FindRolePermissions(ctx context.Context, role Role) ([]string, error) {
+	return r.rolePermissions[role], nil
+}
+
+func (r *fakeRepository) FindPermissionOverrides(ctx context.Context, userID int64) ([]PermissionOverride, error) {
+	return r.overrides[userID], nil
+}
+
+func (r *fakeRepository) FindActiveSanctions(ctx context.Context, userID int64, now time.Time) ([]Sanction, error) {
+	var active []Sanction
+	for _, sanction := range r.sanctions {
+		if sanction.UserID == userID && sanction.IsActive(now) {
+			active = append(active, sanction)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeRepository) EndExpiredUserSanctions(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	r.expiredFor = append(r.expiredFor, userID)
+
+	var ended int64
+	for i := range r.sanctions {
+		sanction := &r.sanctions[i]
+		if sanction.UserID != userID || sanction.EndedAt != nil || sanction.ExpiresAt == nil || sanction.ExpiresAt.After(now) {
+			continue
+		}
+		sanction.EndedAt = sanction.ExpiresAt
+		if sanction.LocksOut() && sanction.PriorRole != "" {
+			r.users[userID].Role = string(sanction.PriorRole)
+		}
+		ended++
+	}
+	return ended, nil
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"socialmediafeed/pkg/logger"
+	"time"
+)
+
+type FollowStatus string
+
+const (
+	FollowStatusFollowing FollowStatus = "following"
+	FollowStatusRequested FollowStatus = "requested"
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrCannotFollowSelf      = errors.New("you cannot follow yourself")
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+)
+
+type FollowRequest struct {
+	RequesterID int64     `json:"requester_id"`
+	Username    string    `json:"username"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FollowNotifier interface {
+	NotifyFollow(ctx context.Context, targetUserID, followerID int64, followerUsername string) error
+	NotifyFollowRequest(ctx context.Context, targetUserID, requesterID int64, requesterUsername string) error
+	NotifyFollowApproved(ctx context.Context, requesterID, targetUserID int64, targetUsername string) error
+}
+
+func (s *Service) SetFollowNotifier(notifier FollowNotifier) {
+	s.notifier = notifier
+}
+
+func (s *Service) notify(send func(FollowNotifier) error) {
+	if s.notifier == nil {
+		return
+	}
+	if err := send(s.notifier); err != nil {
+		logger.Warning("Failed to send follow notification: %v", err)
+	}
+}
@@ -3,10 +3,14 @@ package user
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/permission"
 	"socialmediafeed/pkg/responce"
 	"strconv"
 	"text/template"
+	"time"
 )
 
 type Handler struct {
@@ -25,13 +29,41 @@ func NewHandler(service *Service) *Handler {
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/users/register", h.Register)
 	mux.HandleFunc("POST /api/users/login", h.Login)
-	mux.HandleFunc("GET /api/users/me", h.GetCurrentUser)
+	mux.HandleFunc("GET /api/users/autocomplete", h.AutocompleteUsers)
 	mux.HandleFunc("GET /api/users/{id}", h.GetUserByID)
-	mux.HandleFunc("PUT /api/users/{id}", h.UpdateUser)
-	mux.HandleFunc("DELETE /api/users/{id}", h.DeleteUser)
 	mux.HandleFunc("GET /api/users", h.GetAllUsers)
-	mux.HandleFunc("POST /api/users/{id}/promote", h.PromoteUser)
-	mux.HandleFunc("POST /api/users/{id}/ban", h.BanUser)
+}
+
+func (h *Handler) RegisterAuthenticatedRoutes(mux *http.ServeMux, auth func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/users/me", auth(h.GetCurrentUser))
+	mux.HandleFunc("PUT /api/users/{id}", auth(h.UpdateUser))
+	mux.HandleFunc("DELETE /api/users/{id}", auth(h.DeleteUser))
+	mux.HandleFunc("POST /api/users/{id}/promote", auth(h.PromoteUser))
+	mux.HandleFunc("GET /api/users/me/privacy", auth(h.GetPrivacySettings))
+	mux.HandleFunc("PUT /api/users/me/privacy", auth(h.UpdatePrivacySettings))
+	mux.HandleFunc("POST /api/users/{id}/follow", auth(h.Follow))
+	mux.HandleFunc("DELETE /api/users/{id}/follow", auth(h.Unfollow))
+	mux.HandleFunc("GET /api/users/me/follow-requests", auth(h.GetFollowRequests))
+	mux.HandleFunc("POST /api/users/me/follow-requests/{id}/accept", auth(h.AcceptFollowRequest))
+	mux.HandleFunc("POST /api/users/me/follow-requests/{id}/deny", auth(h.DenyFollowRequest))
+	mux.HandleFunc("POST /api/users/{id}/block", auth(h.Block))
+	mux.HandleFunc("DELETE /api/users/{id}/block", auth(h.Unblock))
+	mux.HandleFunc("GET /api/users/me/blocks", auth(h.GetBlockedUsers))
+	mux.HandleFunc("POST /api/users/{id}/mute", auth(h.Mute))
+	mux.HandleFunc("DELETE /api/users/{id}/mute", auth(h.Unmute))
+	mux.HandleFunc("GET /api/users/me/mutes", auth(h.GetMutedUsers))
+	mux.HandleFunc("POST /api/users/{id}/ban", auth(h.BanUser))
+	mux.HandleFunc("POST /api/users/{id}/unban", auth(h.UnbanUser))
+	mux.HandleFunc("GET /api/users/{id}/sanctions", auth(h.GetSanctions))
+	mux.HandleFunc("POST /api/users/{id}/sanctions", auth(h.SanctionUser))
+	mux.HandleFunc("DELETE /api/users/{id}/sanctions/{sanctionId}", auth(h.RevokeSanction))
+	mux.HandleFunc("GET /api/users/{id}/permissions", auth(h.GetUserPermissions))
+	mux.HandleFunc("PUT /api/users/{id}/permissions/{permission}", auth(h.SetPermissionOverride))
+	mux.HandleFunc("DELETE /api/users/{id}/permissions/{permission}", auth(h.ClearPermissionOverride))
+	mux.HandleFunc("GET /api/admin/permissions", auth(h.GetPermissionCatalog))
+	mux.HandleFunc("GET /api/admin/roles/{role}/permissions", auth(h.GetRolePermissions))
+	mux.HandleFunc("PUT /api/admin/roles/{role}/permissions/{permission}", auth(h.GrantRolePermission))
+	mux.HandleFunc("DELETE /api/admin/roles/{role}/permissions/{permission}", auth(h.RevokeRolePermission))
 }
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +104,10 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, token, err := h.service.Login(r.Context(), req.Email, req.Password)
+	if IsLockedOut(err) {
+		response.Forbidden(w, err.Error())
+		return
+	}
 	if err != nil {
 		response.Unauthorized(w, "Invalid credentials")
 		return
@@ -100,7 +136,7 @@ func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.service.GetUserByID(r.Context(), userID)
+	user, err := h.service.GetUserWithPermissions(r.Context(), userID)
 	if err != nil {
 		response.NotFound(w, "User not found")
 		return
@@ -135,7 +171,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	currentUserID := getUserIDFromContext(r.Context())
-	if currentUserID != id && !isAdmin(r.Context()) {
+	if currentUserID != id && !hasPermission(r.Context(), permission.UserEditAny) {
 		response.Forbidden(w, "Unauthorized")
 		return
 	}
@@ -168,7 +204,7 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	currentUserID := getUserIDFromContext(r.Context())
-	if currentUserID != id && !isAdmin(r.Context()) {
+	if currentUserID != id && !hasPermission(r.Context(), permission.UserDeleteAny) {
 		response.Forbidden(w, "Unauthorized")
 		return
 	}
@@ -182,32 +218,51 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
 
-	limit := 20
-	offset := 0
+	page, err := h.service.GetAllUsers(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
 
-	if limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+
+func (h *Handler) AutocompleteUsers(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	if prefix == "" {
+		response.BadRequest(w, "Query parameter 'prefix' is required")
+		return
 	}
-	if offsetStr != "" {
-		offset, _ = strconv.Atoi(offsetStr)
+
+	limit, err := pagination.LimitFromRequest(r, 10)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
 	}
 
-	users, err := h.service.GetAllUsers(r.Context(), limit, offset)
+	suggestions, err := h.service.AutocompleteUsers(r.Context(), prefix, limit)
 	if err != nil {
 		response.InternalServerError(w, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, users)
+	response.JSON(w, http.StatusOK, suggestions)
 }
 
-
 func (h *Handler) PromoteUser(w http.ResponseWriter, r *http.Request) {
-	if !isAdmin(r.Context()) {
-		response.Forbidden(w, "Admin access required")
+	if !hasPermission(r.Context(), permission.UserPromote) {
+		response.Forbidden(w, "Permission user.promote required")
 		return
 	}
 
@@ -236,8 +291,8 @@ func (h *Handler) PromoteUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) BanUser(w http.ResponseWriter, r *http.Request) {
-	if !canModerate(r.Context()) {
-		response.Forbidden(w, "Moderator access required")
+	if !hasPermission(r.Context(), permission.UserBan) {
+		response.Forbidden(w, "Permission user.ban required")
 		return
 	}
 
@@ -248,31 +303,569 @@ func (h *Handler) BanUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.BanUser(r.Context(), userID); err != nil {
-		response.BadRequest(w, err.Error())
+	var req struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.BanUser(r.Context(), getUserIDFromContext(r.Context()), userID, req.Reason); err != nil {
+		writeSanctionError(w, err)
 		return
 	}
 
 	response.Success(w, "User banned successfully")
 }
 
-func getUserIDFromContext(ctx context.Context) int64 {
-	if userID, ok := ctx.Value("userID").(int64); ok {
-		return userID
+func (h *Handler) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.UserBan) {
+		response.Forbidden(w, "Permission user.ban required")
+		return
 	}
-	return 0
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.UnbanUser(r.Context(), getUserIDFromContext(r.Context()), userID); err != nil {
+		writeSanctionError(w, err)
+		return
+	}
+
+	response.Success(w, "User unbanned successfully")
+}
+
+func (h *Handler) GetSanctions(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.UserBan) {
+		response.Forbidden(w, "Permission user.ban required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetSanctions(r.Context(), userID, params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) SanctionUser(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.UserBan) {
+		response.Forbidden(w, "Permission user.ban required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		Type      string     `json:"type"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+		ExpiresIn string     `json:"expires_in"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	sanctionType, err := ParseSanctionType(req.Type)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		at := req.ExpiresAt.UTC()
+		expiresAt = &at
+	}
+	if req.ExpiresIn != "" {
+		duration, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || duration <= 0 {
+			response.BadRequest(w, "Invalid expires_in, expected a positive duration such as 72h")
+			return
+		}
+		at := time.Now().Add(duration).UTC()
+		expiresAt = &at
+	}
+
+	sanction, err := h.service.SanctionUser(r.Context(), getUserIDFromContext(r.Context()), userID, sanctionType, req.Reason, expiresAt)
+	if err != nil {
+		writeSanctionError(w, err)
+		return
+	}
+
+	response.Created(w, sanction)
+}
+
+func (h *Handler) RevokeSanction(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.UserBan) {
+		response.Forbidden(w, "Permission user.ban required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	sanctionID, err := strconv.ParseInt(r.PathValue("sanctionId"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid sanction ID")
+		return
+	}
+
+	if err := h.service.RevokeSanction(r.Context(), getUserIDFromContext(r.Context()), userID, sanctionID); err != nil {
+		writeSanctionError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *Handler) GetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	settings, err := h.service.GetPrivacySettings(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) UpdatePrivacySettings(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req PrivacySettingsUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request payload")
+		return
+	}
+
+	settings, err := h.service.UpdatePrivacySettings(r.Context(), userID, req)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) Follow(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	targetID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	status, err := h.service.Follow(r.Context(), userID, targetID)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]FollowStatus{"status": status})
+}
+
+func (h *Handler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	targetID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.Unfollow(r.Context(), userID, targetID); err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *Handler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := h.service.GetFollowRequests(r.Context(), userID, params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
 }
 
-func isAdmin(ctx context.Context) bool {
-	if role, ok := ctx.Value("role").(string); ok {
-		return role == "admin"
+func (h *Handler) AcceptFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	requesterID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.AcceptFollowRequest(r.Context(), userID, requesterID); err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	response.Success(w, "Follow request accepted")
+}
+
+func (h *Handler) DenyFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	requesterID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.DenyFollowRequest(r.Context(), userID, requesterID); err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	response.Success(w, "Follow request denied")
+}
+
+func (h *Handler) Block(w http.ResponseWriter, r *http.Request) {
+	h.updateRelation(w, r, h.service.Block, "User blocked")
+}
+
+func (h *Handler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.updateRelation(w, r, h.service.Unblock, "User unblocked")
+}
+
+func (h *Handler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.updateRelation(w, r, h.service.Mute, "User muted")
+}
+
+func (h *Handler) Unmute(w http.ResponseWriter, r *http.Request) {
+	h.updateRelation(w, r, h.service.Unmute, "User unmuted")
+}
+
+func (h *Handler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	h.listRelated(w, r, h.service.GetBlockedUsers)
+}
+
+func (h *Handler) GetMutedUsers(w http.ResponseWriter, r *http.Request) {
+	h.listRelated(w, r, h.service.GetMutedUsers)
+}
+
+func (h *Handler) updateRelation(w http.ResponseWriter, r *http.Request, update func(context.Context, int64, int64) error, message string) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	targetID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := update(r.Context(), userID, targetID); err != nil {
+		writeRelationError(w, err)
+		return
 	}
-	return false
+
+	response.Success(w, message)
 }
 
-func canModerate(ctx context.Context) bool {
-	if role, ok := ctx.Value("role").(string); ok {
-		return role == "admin" || role == "moderator"
+func (h *Handler) listRelated(w http.ResponseWriter, r *http.Request, list func(context.Context, int64, pagination.Params) (pagination.Page[RelatedUser], error)) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		response.Unauthorized(w, "Unauthorized")
+		return
 	}
-	return false
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	page, err := list(r.Context(), userID, params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.CursorPaginated(w, page.Items, page.NextCursor, params.Limit)
+}
+
+func (h *Handler) GetPermissionCatalog(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, h.service.GetPermissionCatalog())
+}
+
+func (h *Handler) GetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	role, err := ParseRole(r.PathValue("role"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	permissions, err := h.service.GetRolePermissions(r.Context(), role)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{"role": role, "permissions": permissions})
+}
+
+func (h *Handler) GrantRolePermission(w http.ResponseWriter, r *http.Request) {
+	h.updateRolePermission(w, r, h.service.GrantRolePermission, "Permission granted")
+}
+
+func (h *Handler) RevokeRolePermission(w http.ResponseWriter, r *http.Request) {
+	h.updateRolePermission(w, r, h.service.RevokeRolePermission, "Permission revoked")
+}
+
+func (h *Handler) updateRolePermission(w http.ResponseWriter, r *http.Request, update func(context.Context, Role, permission.Permission) error, message string) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	role, err := ParseRole(r.PathValue("role"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	p, err := ParsePermission(r.PathValue("permission"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	if err := update(r.Context(), role, p); err != nil {
+		writePermissionError(w, err)
+		return
+	}
+
+	response.Success(w, message)
+}
+
+func (h *Handler) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	permissions, err := h.service.GetUserPermissions(r.Context(), userID)
+	if err != nil {
+		writePermissionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, permissions)
+}
+
+func (h *Handler) SetPermissionOverride(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	p, err := ParsePermission(r.PathValue("permission"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		Granted *bool `json:"granted"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Granted == nil {
+		response.BadRequest(w, "Invalid request payload, expected {\"granted\": true|false}")
+		return
+	}
+
+	override, err := h.service.SetPermissionOverride(r.Context(), getUserIDFromContext(r.Context()), userID, p, *req.Granted)
+	if err != nil {
+		writePermissionError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, override)
+}
+
+func (h *Handler) ClearPermissionOverride(w http.ResponseWriter, r *http.Request) {
+	if !hasPermission(r.Context(), permission.PermissionManage) {
+		response.Forbidden(w, "Permission permission.manage required")
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	p, err := ParsePermission(r.PathValue("permission"))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.service.ClearPermissionOverride(r.Context(), getUserIDFromContext(r.Context()), userID, p); err != nil {
+		writePermissionError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func writeRelationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrFollowRequestNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrBlocked):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrCannotFollowSelf), errors.Is(err, ErrCannotBlockSelf):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func writeSanctionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrSanctionNotFound):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrCannotSanctionStaff):
+		response.Forbidden(w, err.Error())
+	case errors.Is(err, ErrNotBanned):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidSanctionType), errors.Is(err, ErrReasonRequired), errors.Is(err, ErrReasonTooLong),
+		errors.Is(err, ErrExpiryRequired), errors.Is(err, ErrInvalidExpiry), errors.Is(err, ErrBanIsPermanent),
+		errors.Is(err, ErrCannotSanctionSelf):
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func writePermissionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrOverrideNotFound), errors.Is(err, ErrPermissionNotGranted):
+		response.NotFound(w, err.Error())
+	case errors.Is(err, ErrProtectedPermission), errors.Is(err, ErrCannotOverrideSelf):
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalServerError(w, err.Error())
+	}
+}
+
+func getUserIDFromContext(ctx context.Context) int64 {
+	if userID, ok := ctx.Value("userID").(int64); ok {
+		return userID
+	}
+	return 0
+}
+
+func hasPermission(ctx context.Context, p permission.Permission) bool {
+	return permission.Has(permission.FromContext(ctx), p)
 }
@@ -0,0 +1,204 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"socialmediafeed/pkg/permission"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidRole          = errors.New("invalid role, expected user, moderator or admin")
+	ErrUnknownPermission    = errors.New("unknown permission")
+	ErrProtectedPermission  = errors.New("permission.manage cannot be removed from the admin role")
+	ErrPermissionNotGranted = errors.New("role does not have this permission")
+	ErrOverrideNotFound     = errors.New("permission override not found")
+	ErrCannotOverrideSelf   = errors.New("you cannot change your own permissions")
+)
+
+type PermissionOverride struct {
+	UserID     int64     `json:"user_id"`
+	Permission string    `json:"permission"`
+	Granted    bool      `json:"granted"`
+	GrantedBy  int64     `json:"granted_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type UserPermissions struct {
+	UserID      int64                `json:"user_id"`
+	Role        string               `json:"role"`
+	Permissions []string             `json:"permissions"`
+	Overrides   []PermissionOverride `json:"overrides"`
+}
+
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if role == RoleBanned || !isValidRole(role) {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+func ParsePermission(value string) (permission.Permission, error) {
+	p := permission.Permission(value)
+	if !permission.IsValid(p) {
+		return "", ErrUnknownPermission
+	}
+	return p, nil
+}
+
+func (s *Service) attachPermissions(ctx context.Context, u *User) error {
+	u.Permissions = []string{}
+	if u.IsBanned() {
+		return nil
+	}
+
+	granted, err := s.repo.FindRolePermissions(ctx, Role(u.Role))
+	if err != nil {
+		return err
+	}
+	for _, p := range granted {
+		u.GrantPermission(p)
+	}
+
+	overrides, err := s.repo.FindPermissionOverrides(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	for _, override := range overrides {
+		if override.Granted {
+			u.GrantPermission(override.Permission)
+		} else {
+			u.RevokePermission(override.Permission)
+		}
+	}
+
+	sort.Strings(u.Permissions)
+	return nil
+}
+
+func (s *Service) PermissionsFor(ctx context.Context, userID int64) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, err
+	}
+	if err := s.attachPermissions(ctx, u); err != nil {
+		return nil, err
+	}
+	return u.Permissions, nil
+}
+
+func (s *Service) GetPermissionCatalog() []permission.Definition {
+	return permission.Catalog
+}
+
+func (s *Service) GetRolePermissions(ctx context.Context, role Role) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return s.repo.FindRolePermissions(ctx, role)
+}
+
+func (s *Service) GrantRolePermission(ctx context.Context, role Role, p permission.Permission) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return s.repo.AddRolePermission(ctx, role, string(p))
+}
+
+func (s *Service) RevokeRolePermission(ctx context.Context, role Role, p permission.Permission) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if role == RoleAdmin && p == permission.PermissionManage {
+		return ErrProtectedPermission
+	}
+
+	removed, err := s.repo.RemoveRolePermission(ctx, role, string(p))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrPermissionNotGranted
+	}
+	return nil
+}
+
+func (s *Service) GetUserPermissions(ctx context.Context, userID int64) (*UserPermissions, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.attachPermissions(ctx, u); err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.repo.FindPermissionOverrides(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserPermissions{
+		UserID:      u.ID,
+		Role:        u.Role,
+		Permissions: u.Permissions,
+		Overrides:   overrides,
+	}, nil
+}
+
+func (s *Service) SetPermissionOverride(ctx context.Context, actorID, userID int64, p permission.Permission, granted bool) (*PermissionOverride, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if actorID == userID {
+		return nil, ErrCannotOverrideSelf
+	}
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+
+	override := &PermissionOverride{
+		UserID:     userID,
+		Permission: string(p),
+		Granted:    granted,
+		GrantedBy:  actorID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.SavePermissionOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+func (s *Service) ClearPermissionOverride(ctx context.Context, actorID, userID int64, p permission.Permission) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if actorID == userID {
+		return ErrCannotOverrideSelf
+	}
+
+	removed, err := s.repo.DeletePermissionOverride(ctx, userID, string(p))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
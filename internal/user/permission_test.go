@@ -0,0 +1,59 @@
+package user
+
+import (
+	"context"
+	"reflect"
+	"socialmediafeed/pkg/permission"
+	"testing"
+)
+
+func TestPermissionsForAppliesOverrides(t *testing.T) {
+	repo := newFakeRepository(
+		&User{ID: 1, Role: string(RoleModerator)},
+		&User{ID: 2, Role: string(RoleUser)},
+		&User{ID: 3, Role: string(RoleBanned)},
+	)
+	repo.rolePermissions[RoleModerator] = []string{
+		string(permission.ReportReview),
+		string(permission.UserBan),
+		string(permission.PostDeleteAny),
+	}
+	repo.overrides[1] = []PermissionOverride{
+		{UserID: 1, Permission: string(permission.UserBan), Granted: false},
+		{UserID: 1, Permission: string(permission.HashtagMerge), Granted: true},
+	}
+	repo.overrides[2] = []PermissionOverride{
+		{UserID: 2, Permission: string(permission.ReportReview), Granted: true},
+		{UserID: 2, Permission: string(permission.PostDeleteAny), Granted: false},
+	}
+	repo.overrides[3] = []PermissionOverride{
+		{UserID: 3, Permission: string(permission.ReportReview), Granted: true},
+	}
+	service := NewService(repo)
+
+	tests := []struct {
+		name   string
+		userID int64
+		want   []string
+	}{
+		{"role with overrides", 1, []string{
+			string(permission.HashtagMerge),
+			string(permission.PostDeleteAny),
+			string(permission.ReportReview),
+		}},
+		{"grant without role permissions", 2, []string{string(permission.ReportReview)}},
+		{"banned ignores overrides", 3, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.PermissionsFor(context.Background(), tt.userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PermissionsFor(%d) = %v, want %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package user
 
-import "context"
+import (
+	"context"
+	"socialmediafeed/pkg/pagination"
+	"time"
+)
 
 type Repository interface {
 	Create(ctx context.Context, user *User) error
@@ -12,7 +16,39 @@ type Repository interface {
 	Delete(ctx context.Context, id int64) error
 	Exists(ctx context.Context, email string) (bool, error)
 	CountUsers(ctx context.Context) (int, error)
-	FindWithPagination(ctx context.Context, limit, offset int) ([]User, error)
+	FindWithPagination(ctx context.Context, cursor *pagination.Cursor, limit int) ([]User, error)
+	FindByRole(ctx context.Context, role Role, cursor *pagination.Cursor, limit int) ([]User, error)
 	SearchByUsername(ctx context.Context, query string) ([]User, error)
-	Ban(ctx context.Context, userID int64) error
+	AutocompleteByUsername(ctx context.Context, prefix string, limit int) ([]User, error)
+	CreateSanction(ctx context.Context, sanction *Sanction) error
+	FindActiveSanctions(ctx context.Context, userID int64, now time.Time) ([]Sanction, error)
+	FindSanctions(ctx context.Context, userID int64, cursor *pagination.Cursor, limit int) ([]Sanction, error)
+	EndSanction(ctx context.Context, id, userID, revokedBy int64, now time.Time) (bool, error)
+	EndSanctions(ctx context.Context, userID int64, types []SanctionType, revokedBy int64, now time.Time) (int64, error)
+	EndExpiredSanctions(ctx context.Context, now time.Time) (int64, error)
+	EndExpiredUserSanctions(ctx context.Context, userID int64, now time.Time) (int64, error)
+	FindRolePermissions(ctx context.Context, role Role) ([]string, error)
+	AddRolePermission(ctx context.Context, role Role, permission string) error
+	RemoveRolePermission(ctx context.Context, role Role, permission string) (bool, error)
+	FindPermissionOverrides(ctx context.Context, userID int64) ([]PermissionOverride, error)
+	SavePermissionOverride(ctx context.Context, override *PermissionOverride) error
+	DeletePermissionOverride(ctx context.Context, userID int64, permission string) (bool, error)
+	GetPrivacySettings(ctx context.Context, userID int64) (*PrivacySettings, error)
+	SavePrivacySettings(ctx context.Context, userID int64, settings *PrivacySettings) error
+	IsFollowing(ctx context.Context, followerID, followingID int64) (bool, error)
+	Follow(ctx context.Context, followerID, followingID int64) error
+	Unfollow(ctx context.Context, followerID, followingID int64) error
+	CreateFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error)
+	DeleteFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error)
+	AcceptFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error)
+	AcceptAllFollowRequests(ctx context.Context, targetID int64) error
+	FindFollowRequests(ctx context.Context, targetID int64, cursor *pagination.Cursor, limit int) ([]FollowRequest, error)
+	Block(ctx context.Context, blockerID, blockedID int64) error
+	Unblock(ctx context.Context, blockerID, blockedID int64) error
+	IsBlocked(ctx context.Context, userID, otherID int64) (bool, error)
+	IsBlockedWithAny(ctx context.Context, userID int64, usernames []string) (bool, error)
+	FindBlocked(ctx context.Context, blockerID int64, cursor *pagination.Cursor, limit int) ([]RelatedUser, error)
+	Mute(ctx context.Context, muterID, mutedID int64) error
+	Unmute(ctx context.Context, muterID, mutedID int64) error
+	FindMuted(ctx context.Context, muterID int64, cursor *pagination.Cursor, limit int) ([]RelatedUser, error)
 }
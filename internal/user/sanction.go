@@ -0,0 +1,298 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"socialmediafeed/pkg/pagination"
+	"socialmediafeed/pkg/permission"
+	"strings"
+	"time"
+)
+
+type SanctionType string
+
+const (
+	SanctionSuspension SanctionType = "suspension"
+	SanctionBan        SanctionType = "ban"
+	SanctionMute       SanctionType = "mute"
+)
+
+const MaxSanctionReasonLength = 500
+
+var (
+	ErrInvalidSanctionType = errors.New("invalid sanction type, expected suspension, ban or mute")
+	ErrReasonRequired      = errors.New("a reason is required")
+	ErrReasonTooLong       = errors.New("reason exceeds maximum length of 500 characters")
+	ErrExpiryRequired      = errors.New("a suspension needs expires_at or expires_in")
+	ErrInvalidExpiry       = errors.New("expiry must be in the future")
+	ErrBanIsPermanent      = errors.New("bans are permanent, use a suspension for a timed lockout")
+	ErrCannotSanctionSelf  = errors.New("you cannot sanction yourself")
+	ErrCannotSanctionStaff = errors.New("you cannot sanction this user")
+	ErrSanctionNotFound    = errors.New("active sanction not found")
+	ErrNotBanned           = errors.New("user is not banned or suspended")
+
+	ErrAccountBanned    = errors.New("your account has been banned")
+	ErrAccountSuspended = errors.New("your account is suspended")
+	ErrAccountReadOnly  = errors.New("your account is read-only")
+)
+
+type Sanction struct {
+	ID          int64        `json:"id"`
+	UserID      int64        `json:"user_id"`
+	ModeratorID int64        `json:"moderator_id"`
+	Type        SanctionType `json:"type"`
+	Reason      string       `json:"reason"`
+	PriorRole   Role         `json:"prior_role,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	EndedAt     *time.Time   `json:"ended_at,omitempty"`
+	RevokedBy   *int64       `json:"revoked_by,omitempty"`
+}
+
+func ParseSanctionType(value string) (SanctionType, error) {
+	switch sanctionType := SanctionType(value); sanctionType {
+	case SanctionSuspension, SanctionBan, SanctionMute:
+		return sanctionType, nil
+	default:
+		return "", ErrInvalidSanctionType
+	}
+}
+
+func NewSanction(userID, moderatorID int64, sanctionType SanctionType, reason string, expiresAt *time.Time, now time.Time) (*Sanction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	if len(reason) > MaxSanctionReasonLength {
+		return nil, ErrReasonTooLong
+	}
+
+	switch {
+	case sanctionType == SanctionBan && expiresAt != nil:
+		return nil, ErrBanIsPermanent
+	case sanctionType == SanctionSuspension && expiresAt == nil:
+		return nil, ErrExpiryRequired
+	case expiresAt != nil && !expiresAt.After(now):
+		return nil, ErrInvalidExpiry
+	}
+
+	return &Sanction{
+		UserID:      userID,
+		ModeratorID: moderatorID,
+		Type:        sanctionType,
+		Reason:      reason,
+		CreatedAt:   now,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (s *Sanction) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+func (s *Sanction) LocksOut() bool {
+	return s.Type == SanctionBan || s.Type == SanctionSuspension
+}
+
+func (s *Sanction) Err() error {
+	switch {
+	case s.Type == SanctionBan:
+		return fmt.Errorf("%w: %s", ErrAccountBanned, s.Reason)
+	case s.Type == SanctionSuspension:
+		return fmt.Errorf("%w until %s: %s", ErrAccountSuspended, s.ExpiresAt.Format(time.RFC3339), s.Reason)
+	case s.ExpiresAt != nil:
+		return fmt.Errorf("%w until %s: %s", ErrAccountReadOnly, s.ExpiresAt.Format(time.RFC3339), s.Reason)
+	default:
+		return fmt.Errorf("%w: %s", ErrAccountReadOnly, s.Reason)
+	}
+}
+
+func IsLockedOut(err error) bool {
+	return errors.Is(err, ErrAccountBanned) || errors.Is(err, ErrAccountSuspended)
+}
+
+func (s *Service) SanctionUser(ctx context.Context, moderatorID, userID int64, sanctionType SanctionType, reason string, expiresAt *time.Time) (*Sanction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if moderatorID == userID {
+		return nil, ErrCannotSanctionSelf
+	}
+
+	target, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+
+	moderator, err := s.repo.FindByID(ctx, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if moderator == nil {
+		return nil, ErrCannotSanctionStaff
+	}
+	if err := s.attachPermissions(ctx, target); err != nil {
+		return nil, err
+	}
+	if err := s.attachPermissions(ctx, moderator); err != nil {
+		return nil, err
+	}
+	if !canSanction(moderator, target) {
+		return nil, ErrCannotSanctionStaff
+	}
+
+	sanction, err := NewSanction(userID, moderatorID, sanctionType, reason, expiresAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSanction(ctx, sanction); err != nil {
+		return nil, err
+	}
+	return sanction, nil
+}
+
+func (s *Service) BanUser(ctx context.Context, moderatorID, userID int64, reason string) error {
+	_, err := s.SanctionUser(ctx, moderatorID, userID, SanctionBan, reason, nil)
+	return err
+}
+
+func (s *Service) UnbanUser(ctx context.Context, moderatorID, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	target, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrUserNotFound
+	}
+
+	ended, err := s.repo.EndSanctions(ctx, userID, []SanctionType{SanctionBan, SanctionSuspension}, moderatorID, time.Now())
+	if err != nil {
+		return err
+	}
+	if ended == 0 && !target.IsBanned() {
+		return ErrNotBanned
+	}
+	return nil
+}
+
+func (s *Service) RevokeSanction(ctx context.Context, moderatorID, userID, sanctionID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ended, err := s.repo.EndSanction(ctx, sanctionID, userID, moderatorID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ended {
+		return ErrSanctionNotFound
+	}
+	return nil
+}
+
+func (s *Service) GetSanctions(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[Sanction], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sanctions, err := s.repo.FindSanctions(ctx, userID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[Sanction]{}, err
+	}
+
+	return pagination.NewPage(sanctions, params.Limit, func(s Sanction) *pagination.Cursor {
+		return pagination.NewTimeCursor(s.CreatedAt, s.ID)
+	}), nil
+}
+
+func (s *Service) CheckAccess(ctx context.Context, u *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	sanctions, err := s.repo.FindActiveSanctions(ctx, u.ID, now)
+	if err != nil {
+		return err
+	}
+	if sanction := mostSevere(sanctions); sanction != nil && sanction.LocksOut() {
+		return sanction.Err()
+	}
+
+	if !u.IsBanned() {
+		return nil
+	}
+
+	if _, err := s.repo.EndExpiredUserSanctions(ctx, u.ID, now); err != nil {
+		return err
+	}
+	current, err := s.repo.FindByID(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.IsBanned() {
+		return ErrAccountBanned
+	}
+	u.Role = current.Role
+	return s.attachPermissions(ctx, u)
+}
+
+func (s *Service) IsWriteRestricted(ctx context.Context, userID int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	sanctions, err := s.repo.FindActiveSanctions(ctx, userID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if len(sanctions) > 0 {
+		return true, nil
+	}
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return u != nil && u.IsBanned(), nil
+}
+
+func (s *Service) ExpireSanctions(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return s.repo.EndExpiredSanctions(ctx, time.Now())
+}
+
+func canSanction(moderator, target *User) bool {
+	switch {
+	case target.HasPermission(string(permission.PermissionManage)):
+		return false
+	case target.HasPermission(string(permission.UserBan)):
+		return moderator.HasPermission(string(permission.UserSanctionStaff))
+	default:
+		return true
+	}
+}
+
+func mostSevere(sanctions []Sanction) *Sanction {
+	var worst *Sanction
+	for i := range sanctions {
+		sanction := &sanctions[i]
+		switch {
+		case worst == nil:
+			worst = sanction
+		case sanction.Type == SanctionBan:
+			return sanction
+		case sanction.LocksOut() && !worst.LocksOut():
+			worst = sanction
+		case sanction.Type == worst.Type && sanction.ExpiresAt != nil && worst.ExpiresAt != nil && sanction.ExpiresAt.After(*worst.ExpiresAt):
+			worst = sanction
+		}
+	}
+	return worst
+}
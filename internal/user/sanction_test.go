@@ -0,0 +1,182 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"socialmediafeed/pkg/permission"
+	"testing"
+	"time"
+)
+
+func TestNewSanction(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		kind      SanctionType
+		reason    string
+		expiresAt *time.Time
+		want      error
+	}{
+		{"ban", SanctionBan, "spam", nil, nil},
+		{"timed mute", SanctionMute, "flaming", &later, nil},
+		{"permanent mute", SanctionMute, "flaming", nil, nil},
+		{"suspension", SanctionSuspension, "abuse", &later, nil},
+		{"missing reason", SanctionBan, "  ", nil, ErrReasonRequired},
+		{"timed ban", SanctionBan, "spam", &later, ErrBanIsPermanent},
+		{"open suspension", SanctionSuspension, "abuse", nil, ErrExpiryRequired},
+		{"expiry in the past", SanctionMute, "flaming", &earlier, ErrInvalidExpiry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSanction(2, 1, tt.kind, tt.reason, tt.expiresAt, now); err != tt.want {
+				t.Errorf("NewSanction() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanctionIsActive(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	ended := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		sanction Sanction
+		at       time.Time
+		want     bool
+	}{
+		{"permanent", Sanction{}, now, true},
+		{"before expiry", Sanction{ExpiresAt: &later}, now, true},
+		{"at expiry", Sanction{ExpiresAt: &later}, later, false},
+		{"after expiry in another zone", Sanction{ExpiresAt: &later}, later.Add(time.Second).In(time.FixedZone("UTC-10", -10*60*60)), false},
+		{"revoked", Sanction{EndedAt: &ended}, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sanction.IsActive(tt.at); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostSevere(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	later := now.Add(24 * time.Hour)
+
+	mute := Sanction{ID: 1, Type: SanctionMute, ExpiresAt: &later}
+	shortSuspension := Sanction{ID: 2, Type: SanctionSuspension, ExpiresAt: &soon}
+	longSuspension := Sanction{ID: 3, Type: SanctionSuspension, ExpiresAt: &later}
+	ban := Sanction{ID: 4, Type: SanctionBan}
+
+	tests := []struct {
+		name      string
+		sanctions []Sanction
+		want      int64
+	}{
+		{"none", nil, 0},
+		{"mute only", []Sanction{mute}, 1},
+		{"lockout beats mute", []Sanction{mute, shortSuspension}, 2},
+		{"longest suspension", []Sanction{shortSuspension, mute, longSuspension}, 3},
+		{"ban beats everything", []Sanction{longSuspension, ban, mute}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int64
+			if worst := mostSevere(tt.sanctions); worst != nil {
+				got = worst.ID
+			}
+			if got != tt.want {
+				t.Errorf("mostSevere() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanSanction(t *testing.T) {
+	withPermissions := func(permissions ...permission.Permission) *User {
+		u := &User{}
+		for _, p := range permissions {
+			u.GrantPermission(string(p))
+		}
+		return u
+	}
+
+	moderator := withPermissions(permission.UserBan)
+	senior := withPermissions(permission.UserBan, permission.UserSanctionStaff)
+	admin := withPermissions(permission.All()...)
+	member := withPermissions()
+
+	tests := []struct {
+		name      string
+		moderator *User
+		target    *User
+		want      bool
+	}{
+		{"moderator on member", moderator, member, true},
+		{"moderator on moderator", moderator, moderator, false},
+		{"senior on moderator", senior, moderator, true},
+		{"senior on admin", senior, admin, false},
+		{"admin on admin", admin, admin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canSanction(tt.moderator, tt.target); got != tt.want {
+				t.Errorf("canSanction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+
+	repo := newFakeRepository(
+		&User{ID: 1, Role: string(RoleBanned)},
+		&User{ID: 2, Role: string(RoleBanned)},
+		&User{ID: 3, Role: string(RoleUser)},
+		&User{ID: 4, Role: string(RoleBanned)},
+	)
+	repo.sanctions = []Sanction{
+		{ID: 1, UserID: 1, Type: SanctionSuspension, PriorRole: RoleModerator, ExpiresAt: &expired},
+		{ID: 2, UserID: 2, Type: SanctionSuspension, PriorRole: RoleUser, ExpiresAt: &expired},
+		{ID: 3, UserID: 3, Type: SanctionMute, ExpiresAt: &later},
+		{ID: 4, UserID: 4, Type: SanctionSuspension, PriorRole: RoleUser, ExpiresAt: &later},
+	}
+	repo.rolePermissions[RoleModerator] = []string{string(permission.UserBan)}
+	service := NewService(repo)
+	ctx := context.Background()
+
+	returning := &User{ID: 1, Role: string(RoleBanned)}
+	if err := service.CheckAccess(ctx, returning); err != nil {
+		t.Fatalf("CheckAccess() after the suspension expired = %v, want nil", err)
+	}
+	if returning.Role != string(RoleModerator) || !returning.HasPermission(string(permission.UserBan)) {
+		t.Errorf("restored user = role %q, permissions %v, want moderator with %s", returning.Role, returning.Permissions, permission.UserBan)
+	}
+	if len(repo.expiredFor) != 1 || repo.expiredFor[0] != 1 {
+		t.Errorf("expired sanctions for users %v, want only [1]", repo.expiredFor)
+	}
+	if repo.sanctions[1].EndedAt != nil {
+		t.Error("another user's expired suspension was ended by CheckAccess")
+	}
+
+	if err := service.CheckAccess(ctx, &User{ID: 3, Role: string(RoleUser)}); err != nil {
+		t.Errorf("CheckAccess() for a muted user = %v, want nil", err)
+	}
+
+	if err := service.CheckAccess(ctx, &User{ID: 4, Role: string(RoleBanned)}); !errors.Is(err, ErrAccountSuspended) {
+		t.Errorf("CheckAccess() for a suspended user = %v, want %v", err, ErrAccountSuspended)
+	}
+}
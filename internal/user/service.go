@@ -3,13 +3,16 @@ package user
 import (
 	"context"
 	"fmt"
+	"socialmediafeed/pkg/pagination"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Service struct {
-	repo Repository
+	repo     Repository
+	notifier FollowNotifier
 }
 
 func NewService(repo Repository) *Service {
@@ -73,6 +76,14 @@ func (s *Service) Login(ctx context.Context, email, password string) (*User, str
 		return nil, "", fmt.Errorf("invalid credentials")
 	}
 
+	if err := s.CheckAccess(ctx, user); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.attachPermissions(ctx, user); err != nil {
+		return nil, "", err
+	}
+
 	token := generateToken(user.ID)
 
 	return user, token, nil
@@ -94,6 +105,19 @@ func (s *Service) GetUserByID(ctx context.Context, id int64) (*User, error) {
 	return user, nil
 }
 
+func (s *Service) GetUserWithPermissions(ctx context.Context, id int64) (*User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachPermissions(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (s *Service) UpdateUser(ctx context.Context, id int64, username, email string) (*User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -130,11 +154,58 @@ func (s *Service) DeleteUser(ctx context.Context, id int64) error {
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *Service) GetAllUsers(ctx context.Context, limit, offset int) ([]User, error) {
+func (s *Service) GetAllUsers(ctx context.Context, params pagination.Params) (pagination.Page[User], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.FindWithPagination(ctx, limit, offset)
+	users, err := s.repo.FindWithPagination(ctx, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[User]{}, err
+	}
+
+	return pagination.NewPage(users, params.Limit, func(u User) *pagination.Cursor {
+		return pagination.NewTimeCursor(u.CreatedAt, u.ID)
+	}), nil
+}
+
+func (s *Service) GetBannedUsers(ctx context.Context, params pagination.Params) (pagination.Page[User], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	users, err := s.repo.FindByRole(ctx, RoleBanned, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[User]{}, err
+	}
+
+	return pagination.NewPage(users, params.Limit, func(u User) *pagination.Cursor {
+		return pagination.NewTimeCursor(u.UpdatedAt, u.ID)
+	}), nil
+}
+
+func (s *Service) AutocompleteUsers(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	suggestions := []Suggestion{}
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "@")
+	if prefix == "" {
+		return suggestions, nil
+	}
+
+	users, err := s.repo.AutocompleteByUsername(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		suggestions = append(suggestions, Suggestion{ID: u.ID, Username: u.Username})
+	}
+
+	return suggestions, nil
 }
 
 func (s *Service) PromoteUser(ctx context.Context, userID int64, role string) error {
@@ -170,11 +241,274 @@ func (s *Service) PromoteUser(ctx context.Context, userID int64, role string) er
 	return s.repo.Update(ctx, newUser)
 }
 
-func (s *Service) BanUser(ctx context.Context, userID int64) error {
+func (s *Service) GetPrivacySettings(ctx context.Context, userID int64) (*PrivacySettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return s.repo.GetPrivacySettings(ctx, userID)
+}
+
+func (s *Service) UpdatePrivacySettings(ctx context.Context, userID int64, update PrivacySettingsUpdate) (*PrivacySettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	current, err := s.repo.GetPrivacySettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := update.ApplyTo(*current)
+	if err := s.repo.SavePrivacySettings(ctx, userID, &settings); err != nil {
+		return nil, err
+	}
+
+	if current.PrivateAccount && !settings.PrivateAccount {
+		if err := s.repo.AcceptAllFollowRequests(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return &settings, nil
+}
+
+func (s *Service) LikesVisible(ctx context.Context, ownerID, viewerID int64) (bool, error) {
+	if ownerID == viewerID {
+		return true, nil
+	}
+
+	blocked, err := s.repo.IsBlocked(ctx, ownerID, viewerID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	settings, err := s.GetPrivacySettings(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+	if !settings.ShowLikes || !settings.PrivateAccount {
+		return settings.ShowLikes, nil
+	}
+	if viewerID == 0 {
+		return false, nil
+	}
+
+	return s.repo.IsFollowing(ctx, viewerID, ownerID)
+}
+
+func (s *Service) Follow(ctx context.Context, followerID, targetID int64) (FollowStatus, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return s.repo.Ban(ctx, userID)
+	if followerID == targetID {
+		return "", ErrCannotFollowSelf
+	}
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		return "", err
+	}
+	if target == nil {
+		return "", ErrUserNotFound
+	}
+
+	blocked, err := s.repo.IsBlocked(ctx, followerID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", ErrBlocked
+	}
+
+	following, err := s.repo.IsFollowing(ctx, followerID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if following {
+		return FollowStatusFollowing, nil
+	}
+
+	follower, err := s.repo.FindByID(ctx, followerID)
+	if err != nil {
+		return "", err
+	}
+	if follower == nil {
+		return "", ErrUserNotFound
+	}
+
+	settings, err := s.repo.GetPrivacySettings(ctx, targetID)
+	if err != nil {
+		return "", err
+	}
+
+	if settings.PrivateAccount {
+		created, err := s.repo.CreateFollowRequest(ctx, followerID, targetID)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			s.notify(func(n FollowNotifier) error {
+				return n.NotifyFollowRequest(ctx, targetID, followerID, follower.Username)
+			})
+		}
+		return FollowStatusRequested, nil
+	}
+
+	if err := s.repo.Follow(ctx, followerID, targetID); err != nil {
+		return "", err
+	}
+	s.notify(func(n FollowNotifier) error {
+		return n.NotifyFollow(ctx, targetID, followerID, follower.Username)
+	})
+
+	return FollowStatusFollowing, nil
+}
+
+func (s *Service) Unfollow(ctx context.Context, followerID, targetID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := s.repo.DeleteFollowRequest(ctx, followerID, targetID); err != nil {
+		return err
+	}
+	return s.repo.Unfollow(ctx, followerID, targetID)
+}
+
+func (s *Service) GetFollowRequests(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[FollowRequest], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	requests, err := s.repo.FindFollowRequests(ctx, userID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[FollowRequest]{}, err
+	}
+
+	return pagination.NewPage(requests, params.Limit, func(fr FollowRequest) *pagination.Cursor {
+		return pagination.NewTimeCursor(fr.CreatedAt, fr.RequesterID)
+	}), nil
+}
+
+func (s *Service) AcceptFollowRequest(ctx context.Context, userID, requesterID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	accepted, err := s.repo.AcceptFollowRequest(ctx, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return ErrFollowRequestNotFound
+	}
+
+	owner, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		s.notify(func(n FollowNotifier) error {
+			return n.NotifyFollowApproved(ctx, requesterID, userID, owner.Username)
+		})
+	}
+
+	return nil
+}
+
+func (s *Service) DenyFollowRequest(ctx context.Context, userID, requesterID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deleted, err := s.repo.DeleteFollowRequest(ctx, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrFollowRequestNotFound
+	}
+	return nil
+}
+
+func (s *Service) Block(ctx context.Context, blockerID, blockedID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.checkRelationTarget(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+	return s.repo.Block(ctx, blockerID, blockedID)
+}
+
+func (s *Service) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.Unblock(ctx, blockerID, blockedID)
+}
+
+func (s *Service) Mute(ctx context.Context, muterID, mutedID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.checkRelationTarget(ctx, muterID, mutedID); err != nil {
+		return err
+	}
+	return s.repo.Mute(ctx, muterID, mutedID)
+}
+
+func (s *Service) Unmute(ctx context.Context, muterID, mutedID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.repo.Unmute(ctx, muterID, mutedID)
+}
+
+func (s *Service) GetBlockedUsers(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[RelatedUser], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	blocked, err := s.repo.FindBlocked(ctx, userID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[RelatedUser]{}, err
+	}
+	return pagination.NewPage(blocked, params.Limit, relatedUserCursor), nil
+}
+
+func (s *Service) GetMutedUsers(ctx context.Context, userID int64, params pagination.Params) (pagination.Page[RelatedUser], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	muted, err := s.repo.FindMuted(ctx, userID, params.Cursor, params.FetchLimit())
+	if err != nil {
+		return pagination.Page[RelatedUser]{}, err
+	}
+	return pagination.NewPage(muted, params.Limit, relatedUserCursor), nil
+}
+
+func (s *Service) IsBlocked(ctx context.Context, userID, otherID int64) (bool, error) {
+	if userID == 0 || otherID == 0 || userID == otherID {
+		return false, nil
+	}
+	return s.repo.IsBlocked(ctx, userID, otherID)
+}
+
+func (s *Service) BlocksMention(ctx context.Context, authorID int64, usernames []string) (bool, error) {
+	return s.repo.IsBlockedWithAny(ctx, authorID, usernames)
+}
+
+func (s *Service) checkRelationTarget(ctx context.Context, userID, targetID int64) error {
+	if userID == targetID {
+		return ErrCannotBlockSelf
+	}
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func relatedUserCursor(ru RelatedUser) *pagination.Cursor {
+	return pagination.NewTimeCursor(ru.CreatedAt, ru.UserID)
 }
 
 func generateToken(userID int64) string {
@@ -2,6 +2,7 @@ package user
 
 import (
 	"fmt"
+	"socialmediafeed/pkg/permission"
 	"time"
 )
 
@@ -11,6 +12,7 @@ const (
 	RoleUser      Role = "user"
 	RoleModerator Role = "moderator"
 	RoleAdmin     Role = "admin"
+	RoleBanned    Role = "banned"
 )
 
 type User struct {
@@ -24,6 +26,31 @@ type User struct {
 	Permissions  []string  `json:"permission" db:"-"`
 }
 
+type PrivacySettings struct {
+	ShowLikes      bool `json:"show_likes"`
+	PrivateAccount bool `json:"private_account"`
+}
+
+type PrivacySettingsUpdate struct {
+	ShowLikes      *bool `json:"show_likes"`
+	PrivateAccount *bool `json:"private_account"`
+}
+
+func (u PrivacySettingsUpdate) ApplyTo(settings PrivacySettings) PrivacySettings {
+	if u.ShowLikes != nil {
+		settings.ShowLikes = *u.ShowLikes
+	}
+	if u.PrivateAccount != nil {
+		settings.PrivateAccount = *u.PrivateAccount
+	}
+	return settings
+}
+
+type Suggestion struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
 func (u *User) GrantPermission(permission string) {
 	for _, p := range u.Permissions {
 		if p == permission {
@@ -50,8 +77,12 @@ func (u *User) IsModerator() bool {
 	return u.Role == string(RoleModerator)
 }
 
+func (u *User) IsBanned() bool {
+	return u.Role == string(RoleBanned)
+}
+
 func (u *User) CanModerate() bool {
-	return u.IsAdmin() || u.IsModerator()
+	return u.HasPermission(string(permission.ReportReview))
 }
 
 func (u *User) RevokePermission(permission string) {
@@ -90,9 +121,17 @@ func NewAdmin(username, email, passwordHash string) (*User, error) {
 }
 
 func isValidRole(role Role) bool {
-	return true
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin, RoleBanned:
+		return true
+	default:
+		return false
+	}
 }
 
 func applyRolePermissions(user *User) {
-
+	user.Permissions = []string{}
+	for _, p := range permission.DefaultRolePermissions[user.Role] {
+		user.GrantPermission(string(p))
+	}
 }
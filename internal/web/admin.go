@@ -0,0 +1,192 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"socialmediafeed/internal/report"
+	"socialmediafeed/internal/user"
+	"socialmediafeed/pkg/pagination"
+	"strconv"
+)
+
+const adminPanelSize = 10
+
+func (h *Handler) AdminDashboard(w http.ResponseWriter, r *http.Request) {
+	userObj, ok := requireStaff(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	cases, err := h.reportService.GetQueue(ctx, report.StatusOpen, "", pagination.NewParams(0))
+	if err != nil {
+		http.Error(w, "Failed to load report queue", http.StatusInternalServerError)
+		return
+	}
+
+	signups, err := h.userService.GetAllUsers(ctx, pagination.NewParams(adminPanelSize))
+	if err != nil {
+		http.Error(w, "Failed to load recent signups", http.StatusInternalServerError)
+		return
+	}
+
+	banned, err := h.userService.GetBannedUsers(ctx, pagination.NewParams(adminPanelSize))
+	if err != nil {
+		http.Error(w, "Failed to load banned users", http.StatusInternalServerError)
+		return
+	}
+
+	flagged, err := h.hashtagService.GetFlaggedPosts(ctx, pagination.NewParams(adminPanelSize))
+	if err != nil {
+		http.Error(w, "Failed to load flagged hashtags", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.reportService.GetActionLog(ctx, pagination.NewParams(adminPanelSize))
+	if err != nil {
+		http.Error(w, "Failed to load action history", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title":       "Moderation",
+		"CurrentUser": EncodeUserForTemplate(userObj),
+		"CSRFToken":   CSRFToken(r),
+		"Notice":      r.URL.Query().Get("notice"),
+		"Error":       r.URL.Query().Get("error"),
+		"Cases":       cases.Items,
+		"MoreCases":   cases.NextCursor != "",
+		"Signups":     signups.Items,
+		"Banned":      banned.Items,
+		"Flagged":     flagged.Items,
+		"History":     history.Items,
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "pages/admin.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) AdminCasePage(w http.ResponseWriter, r *http.Request) {
+	userObj, ok := requireStaff(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report case ID", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.reportService.GetCase(r.Context(), id)
+	if errors.Is(err, report.ErrCaseNotFound) {
+		http.Error(w, "Report case not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load report case", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title":       "Report Case",
+		"CurrentUser": EncodeUserForTemplate(userObj),
+		"CSRFToken":   CSRFToken(r),
+		"Case":        c,
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "pages/admin_case.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) AdminResolveCase(w http.ResponseWriter, r *http.Request) {
+	userObj, ok := requireStaff(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report case ID", http.StatusBadRequest)
+		return
+	}
+
+	action, err := report.ParseAction(r.FormValue("action"))
+	if err != nil {
+		redirectToAdmin(w, r, "", err.Error())
+		return
+	}
+
+	if _, err := h.reportService.ResolveCase(r.Context(), id, userObj.ID, action, r.FormValue("note")); err != nil {
+		redirectToAdmin(w, r, "", err.Error())
+		return
+	}
+
+	redirectToAdmin(w, r, "Report case #"+strconv.FormatInt(id, 10)+" resolved with "+string(action), "")
+}
+
+func (h *Handler) AdminTakeAction(w http.ResponseWriter, r *http.Request) {
+	userObj, ok := requireStaff(w, r)
+	if !ok {
+		return
+	}
+
+	targetType, err := report.ParseTargetType(r.FormValue("target_type"))
+	if err != nil {
+		redirectToAdmin(w, r, "", err.Error())
+		return
+	}
+
+	targetID, err := strconv.ParseInt(r.FormValue("target_id"), 10, 64)
+	if err != nil {
+		redirectToAdmin(w, r, "", "Invalid target ID")
+		return
+	}
+
+	action, err := report.ParseAction(r.FormValue("action"))
+	if err != nil {
+		redirectToAdmin(w, r, "", err.Error())
+		return
+	}
+
+	if _, err := h.reportService.TakeAction(r.Context(), userObj.ID, targetType, targetID, action, r.FormValue("note")); err != nil {
+		redirectToAdmin(w, r, "", err.Error())
+		return
+	}
+
+	redirectToAdmin(w, r, "Applied "+string(action)+" to "+string(targetType)+" #"+strconv.FormatInt(targetID, 10), "")
+}
+
+func requireStaff(w http.ResponseWriter, r *http.Request) (*user.User, bool) {
+	userObj, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil, false
+	}
+
+	if !userObj.CanModerate() {
+		http.Error(w, "Moderator access required", http.StatusForbidden)
+		return nil, false
+	}
+
+	return userObj, true
+}
+
+func redirectToAdmin(w http.ResponseWriter, r *http.Request, notice, errMessage string) {
+	query := url.Values{}
+	if notice != "" {
+		query.Set("notice", notice)
+	}
+	if errMessage != "" {
+		query.Set("error", errMessage)
+	}
+
+	target := "/admin"
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
@@ -13,15 +13,17 @@ import (
 type ContextKey string
 
 const (
-	UserIDKey ContextKey = "userID"
-	UserKey   ContextKey = "user"
-	RoleKey   ContextKey = "role"
+	UserIDKey      ContextKey = "userID"
+	UserKey        ContextKey = "user"
+	RoleKey        ContextKey = "role"
+	PermissionsKey ContextKey = "permissions"
 )
 
 const (
-	userIDKey = "userID"
-	userKey   = "user"
-	roleKey   = "role"
+	userIDKey      = "userID"
+	userKey        = "user"
+	roleKey        = "role"
+	permissionsKey = "permissions"
 )
 
 type AuthMiddleware struct {
@@ -36,7 +38,11 @@ func NewAuthMiddleware(userService *user.Service) *AuthMiddleware {
 
 func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, userObj, role := m.getUserFromRequest(r)
+		userID, userObj, role, err := m.getUserFromRequest(r)
+		if err != nil {
+			writeAccessError(w, err)
+			return
+		}
 		if userID == 0 {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -48,6 +54,8 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 		ctx = context.WithValue(ctx, userIDKey, userID)
 		ctx = context.WithValue(ctx, userKey, userObj)
 		ctx = context.WithValue(ctx, roleKey, role)
+		ctx = context.WithValue(ctx, PermissionsKey, userObj.Permissions)
+		ctx = context.WithValue(ctx, permissionsKey, userObj.Permissions)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -55,7 +63,11 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 
 func (m *AuthMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, userObj, role := m.getUserFromRequest(r)
+		userID, userObj, role, err := m.getUserFromRequest(r)
+		if err != nil {
+			writeAccessError(w, err)
+			return
+		}
 		if userID != 0 {
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
 			ctx = context.WithValue(ctx, UserKey, userObj)
@@ -63,49 +75,61 @@ func (m *AuthMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 			ctx = context.WithValue(ctx, userIDKey, userID)
 			ctx = context.WithValue(ctx, userKey, userObj)
 			ctx = context.WithValue(ctx, roleKey, role)
+			ctx = context.WithValue(ctx, PermissionsKey, userObj.Permissions)
+			ctx = context.WithValue(ctx, permissionsKey, userObj.Permissions)
 			r = r.WithContext(ctx)
 		}
 		next.ServeHTTP(w, r)
 	}
 }
 
-func (m *AuthMiddleware) getUserFromRequest(r *http.Request) (int64, *user.User, string) {
-	token := ""
-	if cookie, err := r.Cookie("auth_token"); err == nil {
-		token = cookie.Value
-	}
-
-	if token == "" {
-		authHeader := r.Header.Get("Authorization")
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token = strings.TrimPrefix(authHeader, "Bearer ")
-		}
-	}
-
-	if token == "" {
-		token = r.URL.Query().Get("token")
-	}
-
+func (m *AuthMiddleware) getUserFromRequest(r *http.Request) (int64, *user.User, string, error) {
+	token := requestToken(r)
 	if token == "" {
-		return 0, nil, ""
+		return 0, nil, "", nil
 	}
 
 	parts := strings.Split(token, "_")
 	if len(parts) < 2 || parts[0] != "token" {
-		return 0, nil, ""
+		return 0, nil, "", nil
 	}
 
 	userID, err := strconv.ParseInt(parts[1], 10, 64)
 	if err != nil {
-		return 0, nil, ""
+		return 0, nil, "", nil
 	}
 
-	userObj, err := m.userService.GetUserByID(r.Context(), userID)
+	userObj, err := m.userService.GetUserWithPermissions(r.Context(), userID)
 	if err != nil {
-		return 0, nil, ""
+		return 0, nil, "", nil
+	}
+
+	if err := m.userService.CheckAccess(r.Context(), userObj); err != nil {
+		return 0, nil, "", err
+	}
+
+	return userID, userObj, userObj.Role, nil
+}
+
+func writeAccessError(w http.ResponseWriter, err error) {
+	if user.IsLockedOut(err) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, "Failed to check account status", http.StatusInternalServerError)
+}
+
+func requestToken(r *http.Request) string {
+	if cookie, err := r.Cookie("auth_token"); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimPrefix(authHeader, "Bearer ")
 	}
 
-	return userID, userObj, userObj.Role
+	return r.URL.Query().Get("token")
 }
 
 func SetAuthCookie(w http.ResponseWriter, token string) {
@@ -182,11 +206,12 @@ func EncodeUserForTemplate(u *user.User) map[string]interface{} {
 		return nil
 	}
 	return map[string]interface{}{
-		"ID":        u.ID,
-		"Username":  u.Username,
-		"Email":     u.Email,
-		"Role":      u.Role,
-		"CreatedAt": u.CreatedAt,
+		"ID":          u.ID,
+		"Username":    u.Username,
+		"Email":       u.Email,
+		"Role":        u.Role,
+		"CanModerate": u.CanModerate(),
+		"CreatedAt":   u.CreatedAt,
 	}
 }
 
@@ -1,23 +1,31 @@
 package web
 
 import (
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
+	"socialmediafeed/internal/hashtag"
 	"socialmediafeed/internal/post"
+	"socialmediafeed/internal/report"
+	"socialmediafeed/internal/search"
 	"socialmediafeed/internal/user"
+	"socialmediafeed/pkg/pagination"
 	"strconv"
 	"strings"
 	"text/template"
 )
 
 type Handler struct {
-	postService *post.Service
-	userService *user.Service
-	templates   *template.Template
+	postService    *post.Service
+	userService    *user.Service
+	hashtagService *hashtag.Service
+	searchService  *search.Service
+	reportService  *report.Service
+	templates      *template.Template
 }
 
-func NewHandler(postService *post.Service, userService *user.Service) *Handler {
+func NewHandler(postService *post.Service, userService *user.Service, hashtagService *hashtag.Service, searchService *search.Service, reportService *report.Service) *Handler {
 	var allFiles []string
 
 	layoutFiles, _ := filepath.Glob("web/templates/layout/*.html")
@@ -46,9 +54,12 @@ func NewHandler(postService *post.Service, userService *user.Service) *Handler {
 	}
 
 	return &Handler{
-		postService: postService,
-		userService: userService,
-		templates:   templates,
+		postService:    postService,
+		userService:    userService,
+		hashtagService: hashtagService,
+		searchService:  searchService,
+		reportService:  reportService,
+		templates:      templates,
 	}
 }
 
@@ -63,9 +74,16 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /post/{id}", authMiddleware.OptionalAuth(h.PostPage))
 	mux.HandleFunc("GET /profile/{id}", authMiddleware.OptionalAuth(h.ProfilePage))
 	mux.HandleFunc("GET /profile", authMiddleware.RequireAuth(h.MyProfilePage))
+	mux.HandleFunc("GET /search", authMiddleware.OptionalAuth(h.SearchPage))
+	mux.HandleFunc("GET /hashtag/{tag}", authMiddleware.OptionalAuth(h.HashtagPage))
 	mux.HandleFunc("GET /create-post", authMiddleware.RequireAuth(h.CreatePostPage))
 	mux.HandleFunc("POST /create-post", authMiddleware.RequireAuth(h.HandleCreatePost))
 
+	mux.HandleFunc("GET /admin", authMiddleware.OptionalAuth(h.AdminDashboard))
+	mux.HandleFunc("GET /admin/reports/{id}", authMiddleware.OptionalAuth(h.AdminCasePage))
+	mux.HandleFunc("POST /admin/reports/{id}/resolve", authMiddleware.OptionalAuth(RequireCSRF(h.AdminResolveCase)))
+	mux.HandleFunc("POST /admin/actions", authMiddleware.OptionalAuth(RequireCSRF(h.AdminTakeAction)))
+
 	staticDir := http.Dir("web/static")
 	fileServer := http.FileServer(staticDir)
 	staticHandler := http.StripPrefix("/static/", fileServer)
@@ -80,15 +98,27 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 
 func (h *Handler) HomePage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	posts, err := h.postService.GetFeed(ctx, "date", 20, 0)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.postService.GetFeed(ctx, GetUserIDFromContext(ctx), "date", params)
 	if err != nil {
 		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
 		return
 	}
 
+	if err := h.postService.AttachReactions(ctx, GetUserIDFromContext(ctx), page.Items...); err != nil {
+		http.Error(w, "Failed to load reactions", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]interface{}{
-		"Title": "Social Media Feed",
-		"Posts": posts,
+		"Title":      "Social Media Feed",
+		"Posts":      page.Items,
+		"NextCursor": page.NextCursor,
 	}
 
 	if userObj, ok := GetUserFromContext(ctx); ok {
@@ -109,15 +139,24 @@ func (h *Handler) PostPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	post, err := h.postService.GetPostByID(ctx, id)
+	postObj, err := h.postService.GetPostByID(ctx, id, GetUserIDFromContext(ctx))
+	if errors.Is(err, post.ErrPostNotVisible) {
+		http.Error(w, "You don't have access to this post", http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Post not found", http.StatusNotFound)
 		return
 	}
 
+	if err := h.postService.AttachReactions(ctx, GetUserIDFromContext(ctx), postObj); err != nil {
+		http.Error(w, "Failed to load reactions", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]interface{}{
 		"Title": "Post",
-		"Post":  post,
+		"Post":  postObj,
 	}
 
 	if userObj, ok := GetUserFromContext(ctx); ok {
@@ -144,18 +183,37 @@ func (h *Handler) ProfilePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	posts, err := h.postService.GetPostsByAuthor(ctx, id, 20, 0)
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.postService.GetPostsByAuthor(ctx, id, GetUserIDFromContext(ctx), params)
 	if err != nil {
 		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
 		return
 	}
 
+	if err := h.postService.AttachReactions(ctx, GetUserIDFromContext(ctx), page.Items...); err != nil {
+		http.Error(w, "Failed to load reactions", http.StatusInternalServerError)
+		return
+	}
+
 	data := map[string]interface{}{
-		"Title": "Profile",
-		"User":  profileUser,
-		"Posts": posts,
+		"Title":      "Profile",
+		"User":       profileUser,
+		"Posts":      page.Items,
+		"NextCursor": page.NextCursor,
 	}
 
+	followed, err := h.hashtagService.GetFollowedHashtags(ctx, id)
+	if err != nil {
+		http.Error(w, "Failed to load followed hashtags", http.StatusInternalServerError)
+		return
+	}
+	data["FollowedHashtags"] = followed
+
 	if userObj, ok := GetUserFromContext(ctx); ok {
 		data["CurrentUser"] = EncodeUserForTemplate(userObj)
 		data["IsOwnProfile"] = userObj.ID == id
@@ -166,6 +224,107 @@ func (h *Handler) ProfilePage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) HashtagPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tag, err := h.hashtagService.GetHashtagByTag(ctx, r.PathValue("tag"))
+	if err != nil {
+		http.Error(w, "Hashtag not found", http.StatusNotFound)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.postService.GetPostsByHashtag(ctx, tag, GetUserIDFromContext(ctx), params)
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.postService.AttachReactions(ctx, GetUserIDFromContext(ctx), page.Items...); err != nil {
+		http.Error(w, "Failed to load reactions", http.StatusInternalServerError)
+		return
+	}
+
+	related, err := h.hashtagService.GetRelatedHashtags(ctx, tag.Tag, 10)
+	if err != nil {
+		http.Error(w, "Failed to load related hashtags", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title":      "#" + tag.Tag,
+		"Hashtag":    tag,
+		"Related":    related,
+		"Posts":      page.Items,
+		"NextCursor": page.NextCursor,
+	}
+
+	if userObj, ok := GetUserFromContext(ctx); ok {
+		data["CurrentUser"] = EncodeUserForTemplate(userObj)
+
+		following, err := h.hashtagService.IsFollowing(ctx, userObj.ID, tag.ID)
+		if err != nil {
+			http.Error(w, "Failed to load hashtag", http.StatusInternalServerError)
+			return
+		}
+		data["IsFollowing"] = following
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "pages/hashtag.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) SearchPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	data := map[string]interface{}{
+		"Title": "Search",
+		"Query": r.URL.Query().Get("q"),
+		"Type":  r.URL.Query().Get("type"),
+	}
+
+	if userObj, ok := GetUserFromContext(ctx); ok {
+		data["CurrentUser"] = EncodeUserForTemplate(userObj)
+	}
+
+	if data["Query"] != "" {
+		if err := h.loadSearchResults(r, data); err != nil {
+			data["Error"] = err.Error()
+		}
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "pages/search.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) loadSearchResults(r *http.Request, data map[string]interface{}) error {
+	query, err := search.QueryFromRequest(r)
+	if err != nil {
+		return err
+	}
+	query.ViewerID = GetUserIDFromContext(r.Context())
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.searchService.Search(r.Context(), query, params)
+	if err != nil {
+		return err
+	}
+
+	data["Type"] = string(query.Type)
+	data["Results"] = page.Items
+	data["NextCursor"] = page.NextCursor
+	return nil
+}
+
 func (h *Handler) MyProfilePage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID := GetUserIDFromContext(ctx)
@@ -260,13 +419,14 @@ func (h *Handler) HandleCreatePost(w http.ResponseWriter, r *http.Request) {
 
 	content := r.FormValue("content")
 	imageURL := r.FormValue("image_url")
+	visibility := r.FormValue("visibility")
 
 	if content == "" {
 		http.Redirect(w, r, "/create-post?error="+encodeURL("Content is required"), http.StatusSeeOther)
 		return
 	}
 
-	post, err := h.postService.CreatePost(ctx, userID, content, imageURL)
+	post, err := h.postService.CreatePost(ctx, userID, content, imageURL, visibility)
 	if err != nil {
 		http.Redirect(w, r, "/create-post?error="+encodeURL(err.Error()), http.StatusSeeOther)
 		return
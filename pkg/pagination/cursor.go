@@ -0,0 +1,231 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultLimit    = 20
+	DefaultMaxLimit = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+type config struct {
+	secret       []byte
+	defaultLimit int
+	maxLimit     int
+}
+
+var (
+	mu      sync.RWMutex
+	current = config{
+		secret:       randomSecret(),
+		defaultLimit: DefaultLimit,
+		maxLimit:     DefaultMaxLimit,
+	}
+)
+
+func Configure(secret string, defaultLimit, maxLimit int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if secret != "" {
+		current.secret = []byte(secret)
+	}
+	if maxLimit > 0 {
+		current.maxLimit = maxLimit
+	}
+	if defaultLimit > 0 {
+		current.defaultLimit = defaultLimit
+	}
+	if current.defaultLimit > current.maxLimit {
+		current.defaultLimit = current.maxLimit
+	}
+}
+
+func MaxLimit() int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.maxLimit
+}
+
+func ClampLimit(limit int) int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if limit <= 0 {
+		return current.defaultLimit
+	}
+	if limit > current.maxLimit {
+		return current.maxLimit
+	}
+	return limit
+}
+
+type Cursor struct {
+	Key string `json:"k"`
+	ID  int64  `json:"i"`
+}
+
+func NewCursor(key string, id int64) *Cursor {
+	return &Cursor{Key: key, ID: id}
+}
+
+func NewTimeCursor(t time.Time, id int64) *Cursor {
+	return &Cursor{Key: t.Format(time.RFC3339Nano), ID: id}
+}
+
+func (c *Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+func NewScoreCursor(sort string, score float64, asOf time.Time, id int64) *Cursor {
+	key := sort + "|" + strconv.FormatFloat(score, 'g', -1, 64) + "|" + asOf.Format(time.RFC3339Nano)
+	return &Cursor{Key: key, ID: id}
+}
+
+func (c *Cursor) Score(sort string) (float64, time.Time, error) {
+	parts := strings.SplitN(c.Key, "|", 3)
+	if len(parts) != 3 || parts[0] != sort {
+		return 0, time.Time{}, ErrInvalidCursor
+	}
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, time.Time{}, ErrInvalidCursor
+	}
+	asOf, err := time.Parse(time.RFC3339Nano, parts[2])
+	if err != nil {
+		return 0, time.Time{}, ErrInvalidCursor
+	}
+	return score, asOf, nil
+}
+
+func (c *Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sign(payload))
+}
+
+func Decode(token string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !hmac.Equal(mac, sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type Params struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+func NewParams(limit int) Params {
+	return Params{Limit: ClampLimit(limit)}
+}
+
+func LimitFromRequest(r *http.Request, fallback int) (int, error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return ClampLimit(fallback), nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return 0, ErrInvalidLimit
+	}
+	return ClampLimit(limit), nil
+}
+
+func FromRequest(r *http.Request) (Params, error) {
+	limit, err := LimitFromRequest(r, 0)
+	if err != nil {
+		return Params{}, err
+	}
+	params := Params{Limit: limit}
+
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		cursor, err := Decode(token)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+func (p Params) FetchLimit() int {
+	return p.Limit + 1
+}
+
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+func NewPage[T any](items []T, limit int, cursorFor func(T) *Cursor) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	if len(items) <= limit {
+		return Page[T]{Items: items}
+	}
+
+	items = items[:limit]
+	return Page[T]{
+		Items:      items,
+		NextCursor: cursorFor(items[len(items)-1]).Encode(),
+	}
+}
+
+func sign(payload []byte) []byte {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	mac := hmac.New(sha256.New, current.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
@@ -0,0 +1,135 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	Configure("test-secret", 0, 0)
+
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.UTC)
+	decoded, err := Decode(NewTimeCursor(createdAt, 42).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID != 42 {
+		t.Errorf("ID = %d, want 42", decoded.ID)
+	}
+	got, err := decoded.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(createdAt) {
+		t.Errorf("Time() = %v, want %v", got, createdAt)
+	}
+}
+
+func TestDecodeRejectsTamperedCursor(t *testing.T) {
+	Configure("test-secret", 0, 0)
+
+	token := NewCursor("2024-03-01T10:30:00Z", 42).Encode()
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"k":"2024-03-01T10:30:00Z","i":1}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"swapped payload", forged + "." + signature},
+		{"bad signature", encoded + "." + base64.RawURLEncoding.EncodeToString([]byte("nope"))},
+		{"not base64", "!!!." + signature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+
+	Configure("other-secret", 0, 0)
+	defer Configure("test-secret", 0, 0)
+	if _, err := Decode(token); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Decode() with a different secret error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestScoreCursor(t *testing.T) {
+	Configure("test-secret", 0, 0)
+
+	asOf := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	decoded, err := Decode(NewScoreCursor("trending", 1.25, asOf, 7).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	score, gotAsOf, err := decoded.Score("trending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 1.25 || !gotAsOf.Equal(asOf) || decoded.ID != 7 {
+		t.Errorf("Score() = %v, %v, id %d, want 1.25, %v, id 7", score, gotAsOf, decoded.ID, asOf)
+	}
+
+	if _, _, err := decoded.Score("likes"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Score() with another sort error = %v, want %v", err, ErrInvalidCursor)
+	}
+	if _, err := decoded.Time(); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Time() on a score cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	Configure("test-secret", 0, 0)
+	cursorFor := func(id int64) *Cursor {
+		return NewCursor("k", id)
+	}
+
+	page := NewPage([]int64{5, 4, 3}, 3, cursorFor)
+	if len(page.Items) != 3 || page.NextCursor != "" {
+		t.Errorf("last page = %v, next %q, want 3 items and no cursor", page.Items, page.NextCursor)
+	}
+
+	page = NewPage([]int64{5, 4, 3, 2}, 3, cursorFor)
+	if len(page.Items) != 3 {
+		t.Fatalf("items = %v, want 3", page.Items)
+	}
+	next, err := Decode(page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != 3 {
+		t.Errorf("next cursor ID = %d, want 3", next.ID)
+	}
+
+	if page := NewPage[int64](nil, 3, cursorFor); page.Items == nil {
+		t.Error("empty page items = nil, want empty slice")
+	}
+}
+
+func TestClampLimit(t *testing.T) {
+	Configure("", 10, 50)
+	defer Configure("", DefaultLimit, DefaultMaxLimit)
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, 10},
+		{-1, 10},
+		{25, 25},
+		{500, 50},
+	}
+	for _, tt := range tests {
+		if got := ClampLimit(tt.limit); got != tt.want {
+			t.Errorf("ClampLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package permission
+
+import "context"
+
+type Permission string
+
+const (
+	PostEditAny           Permission = "post.edit.any"
+	PostDeleteAny         Permission = "post.delete.any"
+	CommentEditAny        Permission = "comment.edit.any"
+	CommentDeleteAny      Permission = "comment.delete.any"
+	CommentDeletionsView  Permission = "comment.deletions.view"
+	UserEditAny           Permission = "user.edit.any"
+	UserDeleteAny         Permission = "user.delete.any"
+	UserPromote           Permission = "user.promote"
+	UserBan               Permission = "user.ban"
+	UserSanctionStaff     Permission = "user.sanction.staff"
+	ReportReview          Permission = "report.review"
+	HashtagMerge          Permission = "hashtag.merge"
+	HashtagRename         Permission = "hashtag.rename"
+	HashtagAlias          Permission = "hashtag.alias"
+	HashtagBlock          Permission = "hashtag.block"
+	HashtagModerationView Permission = "hashtag.moderation.view"
+	PermissionManage      Permission = "permission.manage"
+)
+
+type Definition struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+var Catalog = []Definition{
+	{PostEditAny, "Edit, lock, pin and change the visibility of any post"},
+	{PostDeleteAny, "Delete any post"},
+	{CommentEditAny, "Edit any comment"},
+	{CommentDeleteAny, "Delete any comment"},
+	{CommentDeletionsView, "View the comment deletion audit log"},
+	{UserEditAny, "Edit any user's profile"},
+	{UserDeleteAny, "Delete any user"},
+	{UserPromote, "Change a user's role"},
+	{UserBan, "Ban, suspend, mute and unban users"},
+	{UserSanctionStaff, "Sanction users who can themselves sanction others"},
+	{ReportReview, "Review and resolve reports and use the moderator dashboard"},
+	{HashtagMerge, "Merge hashtags"},
+	{HashtagRename, "Rename hashtags"},
+	{HashtagAlias, "Manage hashtag aliases"},
+	{HashtagBlock, "Manage blocked hashtags"},
+	{HashtagModerationView, "View flagged posts and the hashtag audit trail"},
+	{PermissionManage, "Edit role permissions and per-user overrides"},
+}
+
+var DefaultRolePermissions = map[string][]Permission{
+	"user": {},
+	"moderator": {
+		PostEditAny, PostDeleteAny,
+		CommentEditAny, CommentDeleteAny, CommentDeletionsView,
+		UserBan, ReportReview,
+	},
+	"admin": All(),
+}
+
+func All() []Permission {
+	permissions := make([]Permission, len(Catalog))
+	for i, definition := range Catalog {
+		permissions[i] = definition.Name
+	}
+	return permissions
+}
+
+func IsValid(p Permission) bool {
+	for _, definition := range Catalog {
+		if definition.Name == p {
+			return true
+		}
+	}
+	return false
+}
+
+func Has(permissions []string, p Permission) bool {
+	for _, granted := range permissions {
+		if granted == string(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func FromContext(ctx context.Context) []string {
+	if permissions, ok := ctx.Value("permissions").([]string); ok {
+		return permissions
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package policy
+
+import "context"
+
+type Permissions interface {
+	PermissionsFor(ctx context.Context, userID int64) ([]string, error)
+}
+
+type Sanctions interface {
+	IsWriteRestricted(ctx context.Context, userID int64) (bool, error)
+}
+
+func PermissionsFor(ctx context.Context, permissions Permissions, userID int64) ([]string, error) {
+	if permissions == nil || userID == 0 {
+		return nil, nil
+	}
+	return permissions.PermissionsFor(ctx, userID)
+}
+
+func CheckCanWrite(ctx context.Context, sanctions Sanctions, userID int64, restricted error) error {
+	if sanctions == nil {
+		return nil
+	}
+
+	isRestricted, err := sanctions.IsWriteRestricted(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if isRestricted {
+		return restricted
+	}
+	return nil
+}
@@ -71,3 +71,15 @@ func Paginated(w http.ResponseWriter, data interface{}, total, page, limit int)
 	}
 	JSON(w, http.StatusOK, response)
 }
+
+func CursorPaginated(w http.ResponseWriter, data interface{}, nextCursor string, limit int) {
+	response := map[string]interface{}{
+		"data": data,
+		"pagination": map[string]interface{}{
+			"limit":       limit,
+			"next_cursor": nextCursor,
+			"has_more":    nextCursor != "",
+		},
+	}
+	JSON(w, http.StatusOK, response)
+}
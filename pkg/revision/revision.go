@@ -0,0 +1,198 @@
+package revision
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+var (
+	ErrVersionNotFound    = errors.New("revision version not found")
+	ErrInvalidVersion     = errors.New("invalid revision version")
+	ErrEditWindowClosed   = errors.New("the edit window for this content has closed")
+	ErrInvalidEditWindows = errors.New("invalid edit windows")
+)
+
+type Revision struct {
+	Version  int       `json:"version"`
+	Content  string    `json:"content"`
+	MediaURL string    `json:"media_url,omitempty"`
+	EditorID int64     `json:"edited_by"`
+	Editor   string    `json:"editor"`
+	Reason   string    `json:"reason,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type History struct {
+	CurrentVersion int        `json:"current_version"`
+	Current        string     `json:"current_content"`
+	Revisions      []Revision `json:"revisions"`
+}
+
+type Change struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type Diff struct {
+	From    int      `json:"from"`
+	To      int      `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+func NewHistory(revisions []Revision, current string) History {
+	if revisions == nil {
+		revisions = []Revision{}
+	}
+	return History{
+		CurrentVersion: len(revisions) + 1,
+		Current:        current,
+		Revisions:      revisions,
+	}
+}
+
+func (h History) Content(version int) (string, error) {
+	switch {
+	case version == h.CurrentVersion:
+		return h.Current, nil
+	case version >= 1 && version < h.CurrentVersion:
+		return h.Revisions[version-1].Content, nil
+	default:
+		return "", ErrVersionNotFound
+	}
+}
+
+func (h History) Diff(from, to int) (Diff, error) {
+	if to == 0 {
+		to = h.CurrentVersion
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	before, err := h.Content(from)
+	if err != nil {
+		return Diff{}, err
+	}
+	after, err := h.Content(to)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return Diff{From: from, To: to, Changes: Compare(before, after)}, nil
+}
+
+func RangeFromRequest(r *http.Request) (int, int, error) {
+	var versions [2]int
+	for i, key := range []string{"from", "to"} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		version, err := strconv.Atoi(value)
+		if err != nil || version <= 0 {
+			return 0, 0, fmt.Errorf("%w: %s", ErrInvalidVersion, key)
+		}
+		versions[i] = version
+	}
+	return versions[0], versions[1], nil
+}
+
+var tokenPattern = regexp.MustCompile(`\s+|[^\s]+`)
+
+func Compare(before, after string) []Change {
+	a := tokenPattern.FindAllString(before, -1)
+	b := tokenPattern.FindAllString(after, -1)
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	changes := []Change{}
+	add := func(op, text string) {
+		if n := len(changes); n > 0 && changes[n-1].Op == op {
+			changes[n-1].Text += text
+			return
+		}
+		changes = append(changes, Change{Op: op, Text: text})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(OpEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(OpDelete, a[i])
+			i++
+		default:
+			add(OpInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(OpDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(OpInsert, b[j])
+	}
+
+	return changes
+}
+
+type EditWindows map[string]time.Duration
+
+func ParseEditWindows(spec string) (EditWindows, error) {
+	windows := make(EditWindows)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		role, value, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(role) == "" {
+			return nil, fmt.Errorf("%w: %q should be role:duration", ErrInvalidEditWindows, entry)
+		}
+
+		window, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || window < 0 {
+			return nil, fmt.Errorf("%w: %q has an invalid duration", ErrInvalidEditWindows, entry)
+		}
+		windows[strings.ToLower(strings.TrimSpace(role))] = window
+	}
+	return windows, nil
+}
+
+func (w EditWindows) Allows(role string, createdAt, now time.Time) bool {
+	if role == "" {
+		role = "user"
+	}
+	window, ok := w[role]
+	if !ok || window == 0 {
+		return true
+	}
+	return now.Sub(createdAt) <= window
+}
@@ -0,0 +1,98 @@
+package revision
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []Change
+	}{
+		{"unchanged", "hello world", "hello world", []Change{{OpEqual, "hello world"}}},
+		{"insert", "hello world", "hello big world", []Change{{OpEqual, "hello "}, {OpInsert, "big "}, {OpEqual, "world"}}},
+		{"delete", "hello big world", "hello world", []Change{{OpEqual, "hello "}, {OpDelete, "big "}, {OpEqual, "world"}}},
+		{"replace", "hello world", "hello there", []Change{{OpEqual, "hello "}, {OpDelete, "world"}, {OpInsert, "there"}}},
+		{"from empty", "", "new", []Change{{OpInsert, "new"}}},
+		{"to empty", "old", "", []Change{{OpDelete, "old"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryDiff(t *testing.T) {
+	h := NewHistory([]Revision{
+		{Version: 1, Content: "first draft"},
+		{Version: 2, Content: "second draft"},
+	}, "final draft")
+
+	if h.CurrentVersion != 3 {
+		t.Fatalf("CurrentVersion = %d, want 3", h.CurrentVersion)
+	}
+
+	diff, err := h.Diff(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{OpDelete, "second"}, {OpInsert, "final"}, {OpEqual, " draft"}}
+	if diff.From != 2 || diff.To != 3 || !reflect.DeepEqual(diff.Changes, want) {
+		t.Errorf("Diff(0, 0) = %+v, want 2 -> 3 %v", diff, want)
+	}
+
+	diff, err = h.Diff(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.From != 1 || diff.To != 3 {
+		t.Errorf("Diff(1, 0) range = %d -> %d, want 1 -> 3", diff.From, diff.To)
+	}
+
+	for _, versions := range [][2]int{{4, 0}, {1, 4}, {-1, 2}} {
+		if _, err := h.Diff(versions[0], versions[1]); !errors.Is(err, ErrVersionNotFound) {
+			t.Errorf("Diff(%d, %d) error = %v, want %v", versions[0], versions[1], err, ErrVersionNotFound)
+		}
+	}
+}
+
+func TestEditWindows(t *testing.T) {
+	windows, err := ParseEditWindows("user:15m, Moderator:1h, admin:0s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		role  string
+		after time.Duration
+		want  bool
+	}{
+		{"", 10 * time.Minute, true},
+		{"user", 15 * time.Minute, true},
+		{"user", 16 * time.Minute, false},
+		{"moderator", 30 * time.Minute, true},
+		{"admin", 48 * time.Hour, true},
+		{"guest", 48 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		if got := windows.Allows(tt.role, createdAt, createdAt.Add(tt.after)); got != tt.want {
+			t.Errorf("Allows(%q, +%s) = %v, want %v", tt.role, tt.after, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"user", ":15m", "user:soon", "user:-1m"} {
+		if _, err := ParseEditWindows(spec); !errors.Is(err, ErrInvalidEditWindows) {
+			t.Errorf("ParseEditWindows(%q) error = %v, want %v", spec, err, ErrInvalidEditWindows)
+		}
+	}
+}
@@ -13,6 +13,9 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
     
+    // Suggest hashtags and usernames while composing
+    document.querySelectorAll('textarea[data-autocomplete]').forEach(setupAutocomplete);
+    
     // Add any client-side functionality here
 });
 
@@ -154,3 +157,119 @@ async function dislikePost(postId) {
     }
 }
 
+
+// Hashtag and @username autocomplete for textareas
+function setupAutocomplete(textarea) {
+    const list = textarea.parentElement.querySelector('.autocomplete-list');
+    if (!list) {
+        return;
+    }
+    
+    let timer = null;
+    
+    textarea.addEventListener('input', function() {
+        clearTimeout(timer);
+        const token = currentToken(textarea);
+        if (!token) {
+            hideAutocomplete(list);
+            return;
+        }
+        timer = setTimeout(() => fetchSuggestions(textarea, list, token), 150);
+    });
+    
+    textarea.addEventListener('blur', function() {
+        // Delay so a click on a suggestion still registers
+        setTimeout(() => hideAutocomplete(list), 150);
+    });
+}
+
+// Returns the #tag or @mention being typed at the cursor, if any
+function currentToken(textarea) {
+    const before = textarea.value.slice(0, textarea.selectionStart);
+    const match = before.match(/(^|[^A-Za-z0-9_])([#@])([A-Za-z0-9_]+)$/);
+    if (!match) {
+        return null;
+    }
+    return {
+        trigger: match[2],
+        prefix: match[3],
+        start: textarea.selectionStart - match[3].length - 1,
+    };
+}
+
+async function fetchSuggestions(textarea, list, token) {
+    const endpoint = token.trigger === '#' ? '/api/hashtags/autocomplete' : '/api/users/autocomplete';
+    
+    try {
+        const response = await fetch(`${endpoint}?prefix=${encodeURIComponent(token.prefix)}&limit=8`);
+        if (!response.ok) {
+            hideAutocomplete(list);
+            return;
+        }
+        
+        const suggestions = await response.json();
+        const values = suggestions.map(s => token.trigger === '#' ? s.tag : s.username);
+        showAutocomplete(textarea, list, token, values);
+    } catch (error) {
+        console.error('Error loading suggestions:', error);
+        hideAutocomplete(list);
+    }
+}
+
+function showAutocomplete(textarea, list, token, values) {
+    list.innerHTML = '';
+    if (values.length === 0) {
+        hideAutocomplete(list);
+        return;
+    }
+    
+    values.forEach(value => {
+        const item = document.createElement('li');
+        item.textContent = token.trigger + value;
+        item.addEventListener('mousedown', function(e) {
+            e.preventDefault();
+            const end = textarea.selectionStart;
+            const insert = token.trigger + value + ' ';
+            textarea.value = textarea.value.slice(0, token.start) + insert + textarea.value.slice(end);
+            textarea.selectionStart = textarea.selectionEnd = token.start + insert.length;
+            textarea.focus();
+            hideAutocomplete(list);
+        });
+        list.appendChild(item);
+    });
+    list.hidden = false;
+}
+
+function hideAutocomplete(list) {
+    list.hidden = true;
+    list.innerHTML = '';
+}
+
+// Follow or unfollow a hashtag from the hashtag page
+async function toggleHashtagFollow(button) {
+    const tag = button.dataset.tag;
+    const following = button.dataset.following === 'true';
+    button.disabled = true;
+    
+    try {
+        const response = await fetch(`/api/hashtags/${encodeURIComponent(tag)}/follow`, {
+            method: following ? 'DELETE' : 'POST',
+            credentials: 'include',
+        });
+        
+        if (response.ok) {
+            button.dataset.following = following ? 'false' : 'true';
+            button.textContent = following ? 'Follow' : 'Unfollow';
+            button.classList.toggle('btn-primary', following);
+            button.classList.toggle('btn-secondary', !following);
+        } else {
+            const data = await response.json();
+            alert(data.error || 'Failed to update follow');
+        }
+    } catch (error) {
+        console.error('Error updating hashtag follow:', error);
+        alert('An error occurred while updating the follow');
+    } finally {
+        button.disabled = false;
+    }
+}